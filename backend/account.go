@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+const (
+	accountDeletionTokenPrefix = "del_"
+	accountDeletionTokenTTL    = 10 * time.Minute
+	// accountDeletionTokenHeader carries the confirmation token on DELETE /api/account.
+	accountDeletionTokenHeader = "X-Confirm-Deletion"
+)
+
+var errInvalidDeletionToken = errors.New("invalid or expired deletion token")
+
+// AccountDeletionReceipt records what DELETE /api/account removed. Revisions include the
+// AI-generated resume and cover letter versions (AIRevisions), which are the only AI output kept.
+type AccountDeletionReceipt struct {
+	ReceiptID     string    `json:"receiptId"`
+	UserID        string    `json:"userId"`
+	DeletedAt     time.Time `json:"deletedAt"`
+	Profile       bool      `json:"profile"`
+	Applications  int64     `json:"applications"` // including trashed ones
+	StatusChanges int64     `json:"statusChanges"`
+	Revisions     int64     `json:"revisions"`
+	AIRevisions   int64     `json:"aiRevisions"`
+	Contacts      int64     `json:"contacts"`
+	Tags          int64     `json:"tags"`
+	Attachments   int64     `json:"attachments"`
+	Renders       int64     `json:"renders"`
+	Offers        int64     `json:"offers"`
+	CalendarFeed  bool      `json:"calendarFeed"`
+	// Blob bytes are removed after the database commit; failures are logged and counted.
+	BlobsDeleted int `json:"blobsDeleted"`
+	BlobsFailed  int `json:"blobsFailed"`
+}
+
+// newAccountDeletionToken returns a random confirmation token and the hash that is stored.
+func newAccountDeletionToken() (string, []byte, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := accountDeletionTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashAccountDeletionToken(token), nil
+}
+
+func hashAccountDeletionToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
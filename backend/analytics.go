@@ -0,0 +1,343 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	maxAnalyticsGroups = 20
+	maxAnalyticsWeeks  = 520
+)
+
+var errInvalidAnalyticsQuery = errors.New("invalid analytics query")
+
+// funnelStages are the pipeline stages in order. An application "reached" a stage if it was
+// ever in that stage or a later one; rejected and withdrawn don't count as stages.
+var funnelStages = []string{statusSaved, statusApplied, statusScreening, statusInterview, statusOffer, statusAccepted}
+
+// Indexes into funnelStages.
+const (
+	stageApplied   = 1
+	stageScreening = 2
+	stageInterview = 3
+	stageOffer     = 4
+)
+
+// AnalyticsQuery filters the applications analytics are computed over. From and To
+// (YYYY-MM-DD, inclusive) bound when applications were created; Tags are lowercased tag names
+// that an application must all have.
+type AnalyticsQuery struct {
+	From string
+	To   string
+	Tags []string
+}
+
+// Analytics summarizes a user's pipeline.
+type Analytics struct {
+	From         string   `json:"from,omitempty"`
+	To           string   `json:"to,omitempty"`
+	Tags         []string `json:"tags"`
+	Applications int      `json:"applications"`
+	// StatusCounts is how many applications are currently in each status.
+	StatusCounts map[string]int    `json:"statusCounts"`
+	Funnel       []FunnelStage     `json:"funnel"`
+	Conversions  []StageConversion `json:"conversions"`
+	// ResponseRate is the share of applications that got any response (moved past applied or
+	// were rejected) out of those that were applied to.
+	Responded    int               `json:"responded"`
+	ResponseRate float64           `json:"responseRate"`
+	TimeInStage  []StageDuration   `json:"timeInStage"`
+	WeeklyVolume []WeeklyVolume    `json:"weeklyVolume"`
+	ByCompany    []GroupConversion `json:"byCompany"`
+	ByRole       []GroupConversion `json:"byRole"`
+}
+
+// FunnelStage is how many applications reached a stage.
+type FunnelStage struct {
+	Status  string `json:"status"`
+	Reached int    `json:"reached"`
+}
+
+// StageConversion is the share of applications that reached From and went on to reach To.
+type StageConversion struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Rate float64 `json:"rate"`
+}
+
+// StageDuration is the median time applications spent in a status before moving on. Only
+// completed stays count, so the status an application is in now isn't included.
+type StageDuration struct {
+	Status     string  `json:"status"`
+	MedianDays float64 `json:"medianDays"`
+	Samples    int     `json:"samples"`
+}
+
+// WeeklyVolume counts applications created and applied to in the week starting WeekStart (a Monday).
+type WeeklyVolume struct {
+	WeekStart string `json:"weekStart"`
+	Created   int    `json:"created"`
+	Applied   int    `json:"applied"`
+}
+
+// GroupConversion is how applications to one company (or for one job title) progressed.
+type GroupConversion struct {
+	Name          string  `json:"name"`
+	Applications  int     `json:"applications"`
+	Applied       int     `json:"applied"`
+	Interviews    int     `json:"interviews"`
+	Offers        int     `json:"offers"`
+	InterviewRate float64 `json:"interviewRate"` // interviews / applied
+}
+
+// parseAnalyticsQuery reads GET /api/analytics query parameters: from=2026-01-01 to=2026-03-31 tag=remote.
+func parseAnalyticsQuery(v url.Values) (AnalyticsQuery, error) {
+	var q AnalyticsQuery
+	var err error
+	if q.From, err = normalizeDate("from", v.Get("from")); err != nil {
+		return AnalyticsQuery{}, fmt.Errorf("%w: from must be a YYYY-MM-DD date", errInvalidAnalyticsQuery)
+	}
+	if q.To, err = normalizeDate("to", v.Get("to")); err != nil {
+		return AnalyticsQuery{}, fmt.Errorf("%w: to must be a YYYY-MM-DD date", errInvalidAnalyticsQuery)
+	}
+	if q.From != "" && q.To != "" && q.From > q.To {
+		return AnalyticsQuery{}, fmt.Errorf("%w: from is after to", errInvalidAnalyticsQuery)
+	}
+	for _, raw := range v["tag"] {
+		for _, part := range strings.Split(raw, ",") {
+			if key := tagKey(part); key != "" && !slices.Contains(q.Tags, key) {
+				q.Tags = append(q.Tags, key)
+			}
+		}
+	}
+	return q, nil
+}
+
+// stageIndex returns a status's position in funnelStages, or -1 for rejected/withdrawn.
+func stageIndex(status string) int {
+	return slices.Index(funnelStages, status)
+}
+
+// newAnalytics returns an empty result for q, with every status present in StatusCounts.
+func newAnalytics(q AnalyticsQuery) Analytics {
+	a := Analytics{
+		From:         q.From,
+		To:           q.To,
+		Tags:         q.Tags,
+		StatusCounts: map[string]int{},
+		TimeInStage:  []StageDuration{},
+		WeeklyVolume: []WeeklyVolume{},
+		ByCompany:    []GroupConversion{},
+		ByRole:       []GroupConversion{},
+	}
+	if a.Tags == nil {
+		a.Tags = []string{}
+	}
+	for status := range statusOrder {
+		a.StatusCounts[status] = 0
+	}
+	for status := range terminalStatuses {
+		a.StatusCounts[status] = 0
+	}
+	return a
+}
+
+// setFunnel fills Funnel, Conversions and ResponseRate from per-stage reached counts.
+func (a *Analytics) setFunnel(reached []int, responded int) {
+	a.Funnel = make([]FunnelStage, len(funnelStages))
+	for i, status := range funnelStages {
+		a.Funnel[i] = FunnelStage{Status: status, Reached: reached[i]}
+	}
+	a.Conversions = make([]StageConversion, 0, len(funnelStages)-1)
+	for i := 1; i < len(funnelStages); i++ {
+		a.Conversions = append(a.Conversions, StageConversion{
+			From: funnelStages[i-1],
+			To:   funnelStages[i],
+			Rate: ratio(reached[i], reached[i-1]),
+		})
+	}
+	a.Responded = responded
+	a.ResponseRate = ratio(responded, reached[stageApplied])
+}
+
+func newGroupConversion(name string, applications, applied, interviews, offers int) GroupConversion {
+	return GroupConversion{
+		Name:          name,
+		Applications:  applications,
+		Applied:       applied,
+		Interviews:    interviews,
+		Offers:        offers,
+		InterviewRate: ratio(interviews, applied),
+	}
+}
+
+// ratio returns n/d rounded to four decimals, or 0 when d is 0.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(d)*10000) / 10000
+}
+
+func secondsToDays(seconds float64) float64 {
+	return math.Round(seconds/86400*100) / 100
+}
+
+// weekStart truncates t to 00:00 UTC on the Monday of its ISO week, like date_trunc('week', t).
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// analyticsApp is the per-application input to computeAnalytics.
+type analyticsApp struct {
+	Status    string
+	Company   string
+	JobTitle  string
+	CreatedAt time.Time
+	AppliedOn string
+	History   []StatusChange // oldest first
+}
+
+// computeAnalytics is the in-process equivalent of dbStore.Analytics, for the memory store.
+// apps must already be filtered by q.
+func computeAnalytics(q AnalyticsQuery, apps []analyticsApp, now time.Time) Analytics {
+	a := newAnalytics(q)
+	a.Applications = len(apps)
+
+	reached := make([]int, len(funnelStages))
+	responded := 0
+	stays := map[string][]float64{}
+	type group struct {
+		name                                      string
+		applications, applied, interviews, offers int
+	}
+	companies := map[string]*group{}
+	roles := map[string]*group{}
+	var minCreated time.Time
+
+	for _, app := range apps {
+		a.StatusCounts[app.Status]++
+		if minCreated.IsZero() || app.CreatedAt.Before(minCreated) {
+			minCreated = app.CreatedAt
+		}
+
+		maxStage := stageIndex(app.Status)
+		rejectedAfterApplying := false
+		for i, h := range app.History {
+			maxStage = max(maxStage, stageIndex(h.ToStatus))
+			if h.ToStatus == statusRejected && h.FromStatus != nil && *h.FromStatus == statusApplied {
+				rejectedAfterApplying = true
+			}
+			if i+1 < len(app.History) {
+				stays[h.ToStatus] = append(stays[h.ToStatus], app.History[i+1].ChangedAt.Sub(h.ChangedAt).Seconds())
+			}
+		}
+		for i := 0; i <= maxStage; i++ {
+			reached[i]++
+		}
+		if maxStage >= stageApplied && (maxStage >= stageScreening || rejectedAfterApplying) {
+			responded++
+		}
+
+		for _, g := range []struct {
+			into map[string]*group
+			name string
+		}{{companies, app.Company}, {roles, app.JobTitle}} {
+			name := strings.TrimSpace(g.name)
+			if name == "" {
+				continue
+			}
+			key := strings.ToLower(name)
+			entry, ok := g.into[key]
+			if !ok {
+				entry = &group{name: name}
+				g.into[key] = entry
+			}
+			if name < entry.name {
+				entry.name = name
+			}
+			entry.applications++
+			if maxStage >= stageApplied {
+				entry.applied++
+			}
+			if maxStage >= stageInterview {
+				entry.interviews++
+			}
+			if maxStage >= stageOffer {
+				entry.offers++
+			}
+		}
+	}
+	a.setFunnel(reached, responded)
+
+	for _, status := range append(slices.Clone(funnelStages), statusRejected, statusWithdrawn) {
+		samples := stays[status]
+		if len(samples) == 0 {
+			continue
+		}
+		sort.Float64s(samples)
+		mid := len(samples) / 2
+		median := samples[mid]
+		if len(samples)%2 == 0 {
+			median = (samples[mid-1] + samples[mid]) / 2
+		}
+		a.TimeInStage = append(a.TimeInStage, StageDuration{Status: status, MedianDays: secondsToDays(median), Samples: len(samples)})
+	}
+
+	groups := func(m map[string]*group) []GroupConversion {
+		list := make([]*group, 0, len(m))
+		for _, g := range m {
+			list = append(list, g)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].applications != list[j].applications {
+				return list[i].applications > list[j].applications
+			}
+			return strings.ToLower(list[i].name) < strings.ToLower(list[j].name)
+		})
+		out := []GroupConversion{}
+		for _, g := range list[:min(len(list), maxAnalyticsGroups)] {
+			out = append(out, newGroupConversion(g.name, g.applications, g.applied, g.interviews, g.offers))
+		}
+		return out
+	}
+	a.ByCompany = groups(companies)
+	a.ByRole = groups(roles)
+
+	lo, hi := minCreated, now
+	if q.From != "" {
+		lo, _ = time.Parse(dateLayout, q.From)
+	}
+	if q.To != "" {
+		hi, _ = time.Parse(dateLayout, q.To)
+	}
+	if lo.IsZero() {
+		return a
+	}
+	lo, hi = weekStart(lo), weekStart(hi)
+	if earliest := hi.AddDate(0, 0, -7*(maxAnalyticsWeeks-1)); lo.Before(earliest) {
+		lo = earliest
+	}
+	created := map[time.Time]int{}
+	applied := map[time.Time]int{}
+	for _, app := range apps {
+		created[weekStart(app.CreatedAt)]++
+		if d, err := time.Parse(dateLayout, app.AppliedOn); err == nil {
+			applied[weekStart(d)]++
+		}
+	}
+	for w := lo; !w.After(hi); w = w.AddDate(0, 0, 7) {
+		a.WeeklyVolume = append(a.WeeklyVolume, WeeklyVolume{WeekStart: w.Format(dateLayout), Created: created[w], Applied: applied[w]})
+	}
+	return a
+}
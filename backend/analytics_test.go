@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// analyticsFixture is one application with its full status history, shared by the
+// computeAnalytics test and the Postgres test so both implementations are held to the same
+// expectations (analyticsFixtureWant).
+type analyticsFixture struct {
+	company, jobTitle string
+	createdAt         time.Time
+	appliedOn         string
+	history           []string // statuses, one per day in days
+	days              []int    // when each status was entered: day of March 2026, at createdAt's hour
+}
+
+func (f analyticsFixture) status() string { return f.history[len(f.history)-1] }
+
+func (f analyticsFixture) statusHistory() []StatusChange {
+	changes := make([]StatusChange, len(f.history))
+	for i, status := range f.history {
+		changes[i] = StatusChange{ToStatus: status, ChangedAt: march(f.days[i], f.createdAt.Hour())}
+		if i > 0 {
+			changes[i].FromStatus = &f.history[i-1]
+		}
+	}
+	return changes
+}
+
+func march(day, hour int) time.Time { return time.Date(2026, time.March, day, hour, 0, 0, 0, time.UTC) }
+
+var analyticsFixtures = []analyticsFixture{
+	{"Acme", "Backend Engineer", march(2, 10), "2026-03-04",
+		[]string{"saved", "applied", "screening", "interview", "offer"}, []int{2, 4, 9, 12, 20}},
+	{"acme", "Backend Engineer", march(3, 9), "2026-03-03",
+		[]string{"applied", "rejected"}, []int{3, 10}},
+	{"Globex", "Data Engineer", march(10, 12), "2026-03-10",
+		[]string{"applied"}, []int{10}},
+	{"Initech", "Backend Engineer", march(11, 8), "",
+		[]string{"saved"}, []int{11}},
+	{"Globex", "Frontend Engineer", march(16, 15), "2026-03-16",
+		[]string{"applied", "screening", "withdrawn"}, []int{16, 18, 21}},
+	// Created before the queried range (day -18 is February 10), so it is left out of everything.
+	{"Hooli", "Backend Engineer", time.Date(2026, time.February, 10, 9, 0, 0, 0, time.UTC), "2026-02-10",
+		[]string{"applied", "interview"}, []int{-18, 1}},
+}
+
+var analyticsFixtureQuery = AnalyticsQuery{From: "2026-03-01", To: "2026-03-22"}
+
+func analyticsFixtureWant() Analytics {
+	want := newAnalytics(analyticsFixtureQuery)
+	want.Applications = 5
+	for status, n := range map[string]int{"saved": 1, "applied": 1, "offer": 1, "rejected": 1, "withdrawn": 1} {
+		want.StatusCounts[status] = n
+	}
+	// Furthest stages: offer, applied (then rejected), applied, saved, screening (then withdrawn).
+	// Three of the four applied-to got a response: the offer, the rejection and the screening.
+	want.setFunnel([]int{5, 4, 2, 1, 1, 0}, 3)
+	want.TimeInStage = []StageDuration{
+		{Status: "saved", MedianDays: 2, Samples: 1},
+		{Status: "applied", MedianDays: 5, Samples: 3}, // 5, 7 and 2 days
+		{Status: "screening", MedianDays: 3, Samples: 2},
+		{Status: "interview", MedianDays: 8, Samples: 1},
+	}
+	want.WeeklyVolume = []WeeklyVolume{
+		{WeekStart: "2026-02-23", Created: 0, Applied: 0},
+		{WeekStart: "2026-03-02", Created: 2, Applied: 2},
+		{WeekStart: "2026-03-09", Created: 2, Applied: 1},
+		{WeekStart: "2026-03-16", Created: 1, Applied: 1},
+	}
+	want.ByCompany = []GroupConversion{
+		newGroupConversion("Acme", 2, 2, 1, 1),
+		newGroupConversion("Globex", 2, 2, 0, 0),
+		newGroupConversion("Initech", 1, 0, 0, 0),
+	}
+	want.ByRole = []GroupConversion{
+		newGroupConversion("Backend Engineer", 3, 2, 1, 1),
+		newGroupConversion("Data Engineer", 1, 1, 0, 0),
+		newGroupConversion("Frontend Engineer", 1, 1, 0, 0),
+	}
+	return want
+}
+
+func checkAnalytics(t *testing.T, got Analytics) {
+	t.Helper()
+	if want := analyticsFixtureWant(); !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		wantJSON, _ := json.MarshalIndent(want, "", "  ")
+		t.Errorf("analytics differ\n got %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestComputeAnalytics(t *testing.T) {
+	q := analyticsFixtureQuery
+	var apps []analyticsApp
+	for _, f := range analyticsFixtures {
+		if created := f.createdAt.Format(dateLayout); created < q.From || created > q.To {
+			continue
+		}
+		apps = append(apps, analyticsApp{
+			Status:    f.status(),
+			Company:   f.company,
+			JobTitle:  f.jobTitle,
+			CreatedAt: f.createdAt,
+			AppliedOn: f.appliedOn,
+			History:   f.statusHistory(),
+		})
+	}
+	checkAnalytics(t, computeAnalytics(q, apps, march(31, 0)))
+}
+
+func TestComputeAnalyticsEmpty(t *testing.T) {
+	got := computeAnalytics(AnalyticsQuery{}, nil, time.Now())
+	if got.Applications != 0 || len(got.WeeklyVolume) != 0 || len(got.TimeInStage) != 0 {
+		t.Errorf("got %+v, want no applications, weeks or stays", got)
+	}
+}
+
+// TestDBAnalytics runs the same fixture through dbStore.Analytics. It needs TEST_DATABASE_URL
+// pointing at a disposable Postgres with Supabase's auth schema (e.g. `supabase start`); the
+// migrations are applied and a throwaway user is created and deleted.
+func TestDBAnalytics(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	t.Setenv("DATABASE_URL", dsn)
+	t.Setenv("DB_SKIP_MIGRATIONS", "")
+	t.Setenv("PGTZ", "UTC") // week and date boundaries are computed in the session time zone
+	ctx := context.Background()
+	s, err := newDBStore(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	userID := uuid.NewString()
+	if _, err := s.pool.Exec(ctx, `insert into auth.users (id) values ($1::uuid)`, userID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.pool.Exec(context.Background(), `delete from auth.users where id = $1::uuid`, userID) })
+
+	for _, f := range analyticsFixtures {
+		id := uuid.NewString()
+		_, err := s.pool.Exec(ctx, `
+			insert into applications (id, user_id, job_title, company, application_status, applied_on, created_at, updated_at)
+			values ($1::uuid, $2::uuid, $3, $4, $5, nullif($6, '')::date, $7, $7)
+		`, id, userID, f.jobTitle, f.company, f.status(), f.appliedOn, f.createdAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range f.statusHistory() {
+			_, err := s.pool.Exec(ctx, `
+				insert into application_status_history (application_id, user_id, from_status, to_status, changed_at)
+				values ($1::uuid, $2::uuid, $3, $4, $5)
+			`, id, userID, h.FromStatus, h.ToStatus, h.ChangedAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	got, err := s.Analytics(ctx, userID, analyticsFixtureQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAnalytics(t, got)
+
+	empty, err := s.Analytics(ctx, uuid.NewString(), AnalyticsQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.Applications != 0 || len(empty.WeeklyVolume) != 0 {
+		t.Errorf("user without applications: %d applications, %d weeks", empty.Applications, len(empty.WeeklyVolume))
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Error codes are part of the API: clients branch on them, so existing codes must not change.
+const (
+	codeBadRequest           = "bad_request"            // malformed JSON, query parameters or headers
+	codeValidation           = "validation_failed"      // well-formed input with invalid values
+	codeUnauthorized         = "unauthorized"           // missing or invalid bearer token
+	codeForbidden            = "forbidden"              // authenticated but not allowed (e.g. bad confirmation token)
+	codeNotFound             = "not_found"              // unknown route or resource
+	codeMethodNotAllowed     = "method_not_allowed"     // route exists but not for this method
+	codeConflict             = "conflict"               // the request conflicts with the resource's state
+	codeVersionConflict      = "version_conflict"       // stale If-Match; details.current holds the server copy
+	codePreconditionRequired = "precondition_required"  // If-Match (or a confirmation header) is missing
+	codePayloadTooLarge      = "payload_too_large"      // body or upload over a size limit
+	codeQuotaExceeded        = "quota_exceeded"         // storage quota used up
+	codeUnsupportedMedia     = "unsupported_media_type" // upload of a disallowed file type
+	codeAIKeyRequired        = "ai_key_required"        // no Gemini API key on the server or request
+	codeAIUpstream           = "ai_upstream_error"      // the AI provider failed or returned unusable output
+	codeRendererUnavailable  = "renderer_unavailable"   // pdflatex is not installed
+	codeRenderFailed         = "render_failed"          // LaTeX compilation failed
+	codeUnavailable          = "service_unavailable"    // the database is not connected yet
+	codeInternal             = "internal_error"         // anything else; the cause is logged, not returned
+)
+
+// apiError is the JSON error envelope every endpoint replies with:
+//
+//	{"code": "not_found", "message": "Application not found", "details": ..., "requestId": "..."}
+//
+// cause is logged for server errors and never sent to the client.
+type apiError struct {
+	status    int
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	cause     error
+}
+
+func newAPIError(status int, code, message string) *apiError {
+	return &apiError{status: status, Code: code, Message: message}
+}
+
+func (e *apiError) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *apiError) Unwrap() error { return e.cause }
+
+func (e *apiError) withDetails(details any) *apiError {
+	e.Details = details
+	return e
+}
+
+func badRequest(message string) *apiError {
+	return newAPIError(http.StatusBadRequest, codeBadRequest, message)
+}
+
+func notFound(message string) *apiError {
+	return newAPIError(http.StatusNotFound, codeNotFound, message)
+}
+
+// invalidJSON reports a request body that could not be decoded.
+func invalidJSON(err error) *apiError {
+	return badRequest("invalid JSON body: " + err.Error())
+}
+
+// aiUpstreamError reports a failed call to the AI provider. Its message is passed on because it
+// usually explains the failure (quota, invalid key, blocked prompt).
+func aiUpstreamError(err error) *apiError {
+	e := newAPIError(http.StatusBadGateway, codeAIUpstream, "AI request failed: "+err.Error())
+	e.cause = err
+	return e
+}
+
+func rendererUnavailable(message string) *apiError {
+	return newAPIError(http.StatusServiceUnavailable, codeRendererUnavailable, message)
+}
+
+// renderFailed reports a LaTeX compile failure; the message carries the pdflatex log excerpt.
+func renderFailed(err error) *apiError {
+	e := newAPIError(http.StatusInternalServerError, codeRenderFailed, err.Error())
+	e.cause = err
+	return e
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, errNotFound) || errors.Is(err, pgx.ErrNoRows)
+}
+
+// orNotFound replaces a not-found error with a 404 carrying message and passes other errors through.
+func orNotFound(err error, message string) error {
+	if isNotFound(err) {
+		return notFound(message)
+	}
+	return err
+}
+
+// validationErrors are sentinel errors whose messages describe bad input and are safe to return.
+var validationErrors = []error{
+	errInvalidStatus, errInvalidSchedule, errInvalidTag, errInvalidContact, errInvalidOffer,
+	errOfferCompare, errInvalidListQuery, errInvalidAnalyticsQuery, errInvalidRevisionSource,
+	errInvalidExport,
+}
+
+// toAPIError classifies err into an envelope with a stable code.
+func toAPIError(err error) *apiError {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var tooLarge *http.MaxBytesError
+	switch {
+	case isNotFound(err):
+		return notFound("Not found")
+	case errors.Is(err, errInvalidStatusTransition):
+		return newAPIError(http.StatusConflict, codeConflict, err.Error())
+	case errors.Is(err, errVersionConflict):
+		return newAPIError(http.StatusPreconditionFailed, codeVersionConflict, err.Error())
+	case errors.Is(err, errPreconditionRequired):
+		return newAPIError(http.StatusPreconditionRequired, codePreconditionRequired, err.Error())
+	case errors.Is(err, errAttachmentQuota):
+		return newAPIError(http.StatusRequestEntityTooLarge, codeQuotaExceeded, err.Error())
+	case errors.Is(err, errUnsupportedAttachment):
+		return newAPIError(http.StatusUnsupportedMediaType, codeUnsupportedMedia, err.Error())
+	case errors.Is(err, errInvalidDeletionToken):
+		return newAPIError(http.StatusForbidden, codeForbidden, err.Error())
+	case errors.Is(err, errMissingAIKey):
+		return newAPIError(http.StatusBadRequest, codeAIKeyRequired, err.Error())
+	case errors.As(err, &tooLarge):
+		return newAPIError(http.StatusRequestEntityTooLarge, codePayloadTooLarge, "request body is too large")
+	}
+	for _, target := range validationErrors {
+		if errors.Is(err, target) {
+			return newAPIError(http.StatusBadRequest, codeValidation, err.Error())
+		}
+	}
+	e := newAPIError(http.StatusInternalServerError, codeInternal, "Internal server error")
+	e.cause = err
+	return e
+}
+
+// writeError replies with err as a JSON error envelope. Server errors are logged with their
+// cause; the client only sees the code, a generic message and the request id.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	e := *toAPIError(err)
+	e.RequestID = requestIDFromContext(r.Context())
+	if e.status >= http.StatusInternalServerError {
+		var cause any = e.Message
+		if e.cause != nil {
+			cause = e.cause
+		}
+		loggerFrom(r.Context()).Error("request failed", "code", e.Code, "err", cause)
+	}
+	h := w.Header()
+	h.Del("Content-Disposition")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(e)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultApplicationPageSize = 50
+	maxApplicationPageSize     = 200
+
+	sortUpdated = "updated"
+	sortCreated = "created"
+	sortCompany = "company"
+
+	// cursorTimeLayout is fixed-width so encoded timestamps also compare correctly as strings.
+	cursorTimeLayout = "2006-01-02T15:04:05.000000000Z"
+)
+
+var errInvalidListQuery = errors.New("invalid list query")
+
+// ApplicationListQuery holds the filters, ordering and page position for listing applications.
+type ApplicationListQuery struct {
+	Statuses []string
+	Company  string
+	Search   string
+	// Tags are lowercased tag names; an application must have all of them.
+	Tags  []string
+	Sort  string
+	Desc  bool
+	Limit int
+	After *listCursor
+	// Trash lists soft-deleted applications instead of live ones.
+	Trash bool
+}
+
+// ApplicationPage is one page of application summaries. NextCursor is empty on the last page.
+type ApplicationPage struct {
+	Items      []ApplicationSummary
+	NextCursor string
+}
+
+// listCursor marks the last row of a page by its sort key and id (keyset pagination).
+type listCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+func encodeListCursor(c listCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeListCursor(s string) (*listCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", errInvalidListQuery)
+	}
+	var c listCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", errInvalidListQuery)
+	}
+	return &c, nil
+}
+
+// parseApplicationListQuery reads GET /api/applications query parameters:
+//
+//	status=applied,interview  company=Acme  q=backend  tag=remote,referral
+//	sort=updated|created|company  order=asc|desc  limit=50  cursor=<nextCursor>  trash=1
+func parseApplicationListQuery(v url.Values) (ApplicationListQuery, error) {
+	q := ApplicationListQuery{
+		Company: strings.TrimSpace(v.Get("company")),
+		Search:  strings.TrimSpace(v.Get("q")),
+		Sort:    strings.ToLower(strings.TrimSpace(v.Get("sort"))),
+		Limit:   defaultApplicationPageSize,
+	}
+
+	switch strings.ToLower(strings.TrimSpace(v.Get("trash"))) {
+	case "", "0", "false":
+	case "1", "true":
+		q.Trash = true
+	default:
+		return ApplicationListQuery{}, fmt.Errorf("%w: trash must be 1 or 0", errInvalidListQuery)
+	}
+
+	for _, raw := range v["status"] {
+		for _, part := range strings.Split(raw, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			status, err := normalizeApplicationStatus(part)
+			if err != nil {
+				return ApplicationListQuery{}, fmt.Errorf("%w: %v", errInvalidListQuery, err)
+			}
+			q.Statuses = append(q.Statuses, status)
+		}
+	}
+
+	for _, raw := range v["tag"] {
+		for _, part := range strings.Split(raw, ",") {
+			if key := tagKey(part); key != "" && !slices.Contains(q.Tags, key) {
+				q.Tags = append(q.Tags, key)
+			}
+		}
+	}
+
+	switch q.Sort {
+	case "":
+		q.Sort = sortUpdated
+		q.Desc = true
+	case sortUpdated, sortCreated:
+		q.Desc = true
+	case sortCompany:
+		q.Desc = false
+	default:
+		return ApplicationListQuery{}, fmt.Errorf("%w: sort must be updated, created or company", errInvalidListQuery)
+	}
+	switch strings.ToLower(strings.TrimSpace(v.Get("order"))) {
+	case "":
+	case "asc":
+		q.Desc = false
+	case "desc":
+		q.Desc = true
+	default:
+		return ApplicationListQuery{}, fmt.Errorf("%w: order must be asc or desc", errInvalidListQuery)
+	}
+
+	if raw := strings.TrimSpace(v.Get("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return ApplicationListQuery{}, fmt.Errorf("%w: limit must be a positive integer", errInvalidListQuery)
+		}
+		if n > maxApplicationPageSize {
+			n = maxApplicationPageSize
+		}
+		q.Limit = n
+	}
+
+	if raw := strings.TrimSpace(v.Get("cursor")); raw != "" {
+		c, err := decodeListCursor(raw)
+		if err != nil {
+			return ApplicationListQuery{}, err
+		}
+		if c.Sort != q.Sort {
+			return ApplicationListQuery{}, fmt.Errorf("%w: cursor was issued for sort=%s", errInvalidListQuery, c.Sort)
+		}
+		q.After = c
+	}
+	return q, nil
+}
+
+// sortKey returns the cursor key of a summary under this query's ordering.
+func (q ApplicationListQuery) sortKey(a ApplicationSummary) string {
+	switch q.Sort {
+	case sortCreated:
+		return a.CreatedAt.UTC().Format(cursorTimeLayout)
+	case sortCompany:
+		return strings.ToLower(a.Company)
+	default:
+		return a.UpdatedAt.UTC().Format(cursorTimeLayout)
+	}
+}
+
+// page trims a result fetched with Limit+1 rows down to Limit and sets NextCursor if more remain.
+func (q ApplicationListQuery) page(items []ApplicationSummary) ApplicationPage {
+	if items == nil {
+		items = []ApplicationSummary{}
+	}
+	if len(items) <= q.Limit {
+		return ApplicationPage{Items: items}
+	}
+	items = items[:q.Limit]
+	last := items[len(items)-1]
+	return ApplicationPage{
+		Items:      items,
+		NextCursor: encodeListCursor(listCursor{Sort: q.Sort, Key: q.sortKey(last), ID: last.ID}),
+	}
+}
+
+// escapeLike escapes LIKE/ILIKE wildcards in user-provided search text.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func parseCursorTime(key string) (time.Time, error) {
+	t, err := time.Parse(cursorTimeLayout, key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: malformed cursor", errInvalidListQuery)
+	}
+	return t, nil
+}
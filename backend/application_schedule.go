@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// Follow up when an application has sat in one status this long.
+	followUpAfterApplied   = 14 * 24 * time.Hour
+	followUpAfterScreening = 7 * 24 * time.Hour
+	followUpAfterInterview = 7 * 24 * time.Hour
+
+	nextActionApply     = "apply"
+	nextActionInterview = "interview"
+	nextActionFollowUp  = "follow_up"
+)
+
+var errInvalidSchedule = errors.New("invalid schedule")
+
+var interviewFormats = map[string]bool{
+	"phone":     true,
+	"video":     true,
+	"onsite":    true,
+	"take_home": true,
+	"other":     true,
+}
+
+// InterviewRound is one scheduled interview for an application.
+type InterviewRound struct {
+	ScheduledAt time.Time `json:"scheduledAt"`
+	Format      string    `json:"format"` // phone, video, onsite, take_home or other
+	Interviewer string    `json:"interviewer"`
+	Notes       string    `json:"notes"`
+}
+
+// ApplicationSchedule is the subset of an application that next actions are computed from.
+type ApplicationSchedule struct {
+	ID                string           `json:"id"`
+	JobTitle          string           `json:"jobTitle"`
+	Company           string           `json:"company"`
+	ApplicationStatus string           `json:"applicationStatus"`
+	Deadline          string           `json:"deadline,omitempty"`
+	AppliedOn         string           `json:"appliedOn,omitempty"`
+	Interviews        []InterviewRound `json:"interviews"`
+	// StatusChangedAt is when the application last changed status (or was created).
+	StatusChangedAt time.Time `json:"statusChangedAt"`
+}
+
+// Reminder is a next action that is overdue (or due soon) for one application.
+type Reminder struct {
+	ApplicationID     string    `json:"applicationId"`
+	JobTitle          string    `json:"jobTitle"`
+	Company           string    `json:"company"`
+	ApplicationStatus string    `json:"applicationStatus"`
+	Action            string    `json:"action"`
+	Due               time.Time `json:"due"`
+	Overdue           bool      `json:"overdue"`
+}
+
+// normalizeSchedule validates an application's dates and interview rounds in place. Interviews
+// are sorted by time.
+func normalizeSchedule(app *Application) error {
+	var err error
+	if app.Deadline, err = normalizeDate("deadline", app.Deadline); err != nil {
+		return err
+	}
+	if app.AppliedOn, err = normalizeDate("appliedOn", app.AppliedOn); err != nil {
+		return err
+	}
+	if app.Interviews == nil {
+		app.Interviews = []InterviewRound{}
+	}
+	for i := range app.Interviews {
+		round := &app.Interviews[i]
+		if round.ScheduledAt.IsZero() {
+			return fmt.Errorf("%w: interviews[%d].scheduledAt is required", errInvalidSchedule, i)
+		}
+		round.Format = strings.ToLower(strings.TrimSpace(round.Format))
+		if round.Format == "" {
+			round.Format = "other"
+		}
+		if !interviewFormats[round.Format] {
+			return fmt.Errorf("%w: interviews[%d].format %q (use phone, video, onsite, take_home or other)", errInvalidSchedule, i, round.Format)
+		}
+	}
+	sort.SliceStable(app.Interviews, func(i, j int) bool {
+		return app.Interviews[i].ScheduledAt.Before(app.Interviews[j].ScheduledAt)
+	})
+	return nil
+}
+
+// defaultAppliedOn sets appliedOn to today when a blank one moves out of "saved" (a new
+// application counts as moving from "saved"). Later saves leave a blank appliedOn blank, so a
+// date is never stamped on an application that was applied to long before.
+func defaultAppliedOn(app *Application, from string, now time.Time) {
+	if app.AppliedOn == "" && from == statusSaved && app.ApplicationStatus != statusSaved && !terminalStatuses[app.ApplicationStatus] {
+		app.AppliedOn = now.UTC().Format(dateLayout)
+	}
+}
+
+func normalizeDate(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s must be a YYYY-MM-DD date", errInvalidSchedule, field)
+	}
+	return t.Format(dateLayout), nil
+}
+
+// nextAction works out what the user should do next for an application and when:
+//
+//   - an upcoming interview round is always next;
+//   - saved applications are due by their deadline (end of day, UTC);
+//   - applied/screening/interview applications need a follow-up once they have sat
+//     in that status (or since the last interview) for the follow-up window.
+//
+// Offers and terminal statuses have no next action.
+func (s ApplicationSchedule) nextAction(now time.Time) (string, *time.Time) {
+	if terminalStatuses[s.ApplicationStatus] || s.ApplicationStatus == statusOffer {
+		return "", nil
+	}
+
+	var lastRound time.Time
+	for _, round := range s.Interviews {
+		if round.ScheduledAt.After(now) {
+			due := round.ScheduledAt
+			return nextActionInterview, &due
+		}
+		lastRound = round.ScheduledAt
+	}
+
+	base := s.StatusChangedAt
+	var wait time.Duration
+	switch s.ApplicationStatus {
+	case statusSaved:
+		if s.Deadline == "" {
+			return "", nil
+		}
+		d, err := time.Parse(dateLayout, s.Deadline)
+		if err != nil {
+			return "", nil
+		}
+		due := d.Add(24*time.Hour - time.Second)
+		return nextActionApply, &due
+	case statusApplied:
+		if d, err := time.Parse(dateLayout, s.AppliedOn); err == nil {
+			base = d
+		}
+		wait = followUpAfterApplied
+	case statusScreening:
+		wait = followUpAfterScreening
+	case statusInterview:
+		wait = followUpAfterInterview
+	default:
+		return "", nil
+	}
+	if lastRound.After(base) {
+		base = lastRound
+	}
+	due := base.Add(wait)
+	return nextActionFollowUp, &due
+}
+
+// applyNextAction fills the computed NextAction/NextActionDue fields of app.
+func applyNextAction(app *Application, statusChangedAt, now time.Time) {
+	app.NextAction, app.NextActionDue = ApplicationSchedule{
+		ApplicationStatus: app.ApplicationStatus,
+		Deadline:          app.Deadline,
+		AppliedOn:         app.AppliedOn,
+		Interviews:        app.Interviews,
+		StatusChangedAt:   statusChangedAt,
+	}.nextAction(now)
+}
+
+// buildReminders returns next actions that are overdue, or due before now+within, soonest first.
+func buildReminders(schedules []ApplicationSchedule, now time.Time, within time.Duration) []Reminder {
+	out := []Reminder{}
+	horizon := now.Add(within)
+	for _, s := range schedules {
+		action, due := s.nextAction(now)
+		if due == nil || due.After(horizon) {
+			continue
+		}
+		out = append(out, Reminder{
+			ApplicationID:     s.ID,
+			JobTitle:          s.JobTitle,
+			Company:           s.Company,
+			ApplicationStatus: s.ApplicationStatus,
+			Action:            action,
+			Due:               *due,
+			Overdue:           due.Before(now),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Due.Before(out[j].Due) })
+	return out
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Application pipeline statuses. An application moves forward through
+// saved -> applied -> screening -> interview -> offer and ends in one of the
+// terminal statuses (accepted, rejected, withdrawn).
+const (
+	statusSaved     = "saved"
+	statusApplied   = "applied"
+	statusScreening = "screening"
+	statusInterview = "interview"
+	statusOffer     = "offer"
+	statusAccepted  = "accepted"
+	statusRejected  = "rejected"
+	statusWithdrawn = "withdrawn"
+)
+
+var (
+	errInvalidStatus           = errors.New("invalid application status")
+	errInvalidStatusTransition = errors.New("invalid application status transition")
+)
+
+// statusOrder is the position of each non-terminal status in the pipeline.
+var statusOrder = map[string]int{
+	statusSaved:     0,
+	statusApplied:   1,
+	statusScreening: 2,
+	statusInterview: 3,
+	statusOffer:     4,
+}
+
+var terminalStatuses = map[string]bool{
+	statusAccepted:  true,
+	statusRejected:  true,
+	statusWithdrawn: true,
+}
+
+// StatusChange is a single recorded transition of an application's status.
+type StatusChange struct {
+	FromStatus *string   `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
+
+// normalizeApplicationStatus lowercases/trims a status, defaulting blank values to "applied"
+// (for new and imported applications; see normalizeUpdateStatus).
+func normalizeApplicationStatus(status string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(status))
+	if s == "" {
+		return statusApplied, nil
+	}
+	if _, ok := statusOrder[s]; ok {
+		return s, nil
+	}
+	if terminalStatuses[s] {
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: %q", errInvalidStatus, status)
+}
+
+// normalizeUpdateStatus is normalizeApplicationStatus for updates, where a blank status means
+// "keep the current status" and is returned as "".
+func normalizeUpdateStatus(status string) (string, error) {
+	if strings.TrimSpace(status) == "" {
+		return "", nil
+	}
+	return normalizeApplicationStatus(status)
+}
+
+// checkStatusTransition reports whether an application may move from one status to another.
+// Applications may skip ahead in the pipeline but never move backwards; accepted is only
+// reachable from offer, and terminal statuses are final.
+func checkStatusTransition(from, to string) error {
+	if from == to {
+		return nil
+	}
+	if terminalStatuses[from] {
+		return fmt.Errorf("%w: %s is final", errInvalidStatusTransition, from)
+	}
+	switch to {
+	case statusAccepted:
+		if from == statusOffer {
+			return nil
+		}
+	case statusRejected, statusWithdrawn:
+		return nil
+	default:
+		if statusOrder[to] > statusOrder[from] {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", errInvalidStatusTransition, from, to)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultAttachmentMaxMB    = 10
+	defaultAttachmentQuotaMB  = 100
+	maxAttachmentFilenameSize = 255
+)
+
+var (
+	errAttachmentQuota       = errors.New("attachment quota exceeded")
+	errUnsupportedAttachment = errors.New("unsupported attachment type")
+)
+
+// Attachment is a file (offer letter, job posting snapshot, notes...) stored with an application.
+type Attachment struct {
+	ID            string    `json:"id"`
+	ApplicationID string    `json:"applicationId"`
+	Filename      string    `json:"filename"`
+	ContentType   string    `json:"contentType"`
+	Size          int64     `json:"size"`
+	SHA256        string    `json:"sha256"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// BlobKey locates the bytes in the blob store.
+	BlobKey string `json:"-"`
+}
+
+// AttachmentUsage reports how much of the per-user attachment quota is used.
+type AttachmentUsage struct {
+	UsedBytes    int64 `json:"usedBytes"`
+	QuotaBytes   int64 `json:"quotaBytes"`
+	MaxFileBytes int64 `json:"maxFileBytes"`
+}
+
+// attachmentLimits caps the size of one upload and the total stored per user.
+type attachmentLimits struct {
+	MaxFileBytes int64
+	QuotaBytes   int64
+}
+
+// attachmentLimitsFromEnv reads ATTACHMENT_MAX_MB (largest single file) and ATTACHMENT_QUOTA_MB
+// (total per user, trashed applications included).
+func attachmentLimitsFromEnv() (attachmentLimits, error) {
+	megabytes := func(name string, def int64) (int64, error) {
+		raw := strings.TrimSpace(os.Getenv(name))
+		if raw == "" {
+			return def << 20, nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid %s %q (want a positive whole number of megabytes)", name, raw)
+		}
+		return n << 20, nil
+	}
+	maxFile, err := megabytes("ATTACHMENT_MAX_MB", defaultAttachmentMaxMB)
+	if err != nil {
+		return attachmentLimits{}, err
+	}
+	quota, err := megabytes("ATTACHMENT_QUOTA_MB", defaultAttachmentQuotaMB)
+	if err != nil {
+		return attachmentLimits{}, err
+	}
+	return attachmentLimits{MaxFileBytes: maxFile, QuotaBytes: quota}, nil
+}
+
+// Content types accepted for upload, keyed by the type sniffed from the file's first bytes.
+// Containers that can't be told apart by content (zip, OLE) are resolved by file extension.
+var attachmentTypes = map[string]string{
+	"application/pdf": "application/pdf",
+	"image/png":       "image/png",
+	"image/jpeg":      "image/jpeg",
+	"image/gif":       "image/gif",
+	"image/webp":      "image/webp",
+}
+
+var attachmentTextTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".csv":      "text/csv",
+}
+
+var attachmentZipTypes = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".odt":  "application/vnd.oasis.opendocument.text",
+}
+
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// detectAttachmentType decides the stored content type from the file contents, ignoring the
+// type the client claimed. Anything outside the allowlist (HTML, scripts, archives,
+// executables...) is rejected.
+func detectAttachmentType(data []byte, filename string) (string, error) {
+	sniffed := http.DetectContentType(data)
+	base, params, _ := mime.ParseMediaType(sniffed)
+	ext := strings.ToLower(path.Ext(filename))
+
+	if t, ok := attachmentTypes[base]; ok {
+		return t, nil
+	}
+	switch base {
+	case "text/plain":
+		t := "text/plain"
+		if mapped, ok := attachmentTextTypes[ext]; ok {
+			t = mapped
+		}
+		if cs := params["charset"]; cs != "" {
+			t += "; charset=" + cs
+		}
+		return t, nil
+	case "application/zip":
+		if t, ok := attachmentZipTypes[ext]; ok {
+			return t, nil
+		}
+	case "application/octet-stream":
+		if ext == ".doc" && bytes.HasPrefix(data, oleSignature) {
+			return "application/msword", nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s (allowed: PDF, PNG, JPEG, GIF, WebP, plain text, Markdown, CSV, .doc, .docx, .odt)", errUnsupportedAttachment, base)
+}
+
+// sanitizeAttachmentFilename keeps the base name of an uploaded file, without control
+// characters, path separators or a length beyond 255 bytes.
+func sanitizeAttachmentFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '/' || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for len(name) > maxAttachmentFilenameSize {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// attachmentBlobKey is where an attachment's bytes are stored.
+func attachmentBlobKey(userID, appID, attachmentID string) string {
+	return "attachments/" + userID + "/" + appID + "/" + attachmentID
+}
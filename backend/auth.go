@@ -28,6 +28,9 @@ type jwtVerifier struct {
 	aud     string
 	apiKey  string
 
+	// devUserID, when set, bypasses verification (STORE_BACKEND=memory only).
+	devUserID string
+
 	mu       sync.RWMutex
 	keysByID map[string]any
 	fetched  time.Time
@@ -69,6 +72,11 @@ func newJWTVerifierFromEnv() (*jwtVerifier, error) {
 	}, nil
 }
 
+// newDevVerifier returns a verifier that accepts any bearer token as devUserID.
+func newDevVerifier(devUserID string) *jwtVerifier {
+	return &jwtVerifier{devUserID: devUserID, keysByID: map[string]any{}}
+}
+
 func (v *jwtVerifier) VerifyAndGetUserID(ctx context.Context, tokenString string) (string, error) {
 	tokenString = strings.TrimSpace(tokenString)
 	if tokenString == "" {
@@ -253,9 +261,14 @@ func curveFor(crv string) elliptic.Curve {
 
 func requireAuth(verifier *jwtVerifier, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if verifier.devUserID != "" {
+			next(w, r.WithContext(withUser(r.Context(), verifier.devUserID)))
+			return
+		}
+
 		auth := r.Header.Get("Authorization")
 		if !strings.HasPrefix(auth, "Bearer ") {
-			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, "missing bearer token"))
 			return
 		}
 		token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
@@ -264,11 +277,11 @@ func requireAuth(verifier *jwtVerifier, next http.HandlerFunc) http.HandlerFunc
 
 		userID, err := verifier.VerifyAndGetUserID(ctx, token)
 		if err != nil {
-			http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+			writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, "unauthorized: "+err.Error()))
 			return
 		}
 
-		next(w, r.WithContext(context.WithValue(r.Context(), ctxUserID, userID)))
+		next(w, r.WithContext(withUser(r.Context(), userID)))
 	}
 }
 
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore holds attachment bytes. Keys are slash-separated paths chosen by the server
+// (user/application/attachment ids), never by clients.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns the blob's contents, or errNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes a blob; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	_ BlobStore = (*localBlobStore)(nil)
+	_ BlobStore = (*s3BlobStore)(nil)
+)
+
+const (
+	blobBackendLocal = "local"
+	blobBackendS3    = "s3"
+
+	defaultBlobDir = "data/blobs"
+)
+
+// newBlobStoreFromEnv builds the blob store selected by BLOB_BACKEND ("local" by default, or "s3").
+func newBlobStoreFromEnv() (BlobStore, error) {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv("BLOB_BACKEND")))
+	switch backend {
+	case "", blobBackendLocal:
+		dir := strings.TrimSpace(os.Getenv("BLOB_LOCAL_DIR"))
+		if dir == "" {
+			dir = defaultBlobDir
+		}
+		return newLocalBlobStore(dir)
+	case blobBackendS3:
+		return newS3BlobStoreFromEnv()
+	default:
+		return nil, fmt.Errorf("unknown BLOB_BACKEND %q (use local or s3)", backend)
+	}
+}
+
+// localBlobStore keeps blobs as files under a root directory.
+type localBlobStore struct {
+	root string
+}
+
+func newLocalBlobStore(root string) (*localBlobStore, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0o750); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+	return &localBlobStore{root: abs}, nil
+}
+
+// path maps a key to a file under root, rejecting keys that would escape it.
+func (l *localBlobStore) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+func (l *localBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename so readers never see a partial blob.
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (l *localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotFound
+	}
+	return f, err
+}
+
+func (l *localBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// Tidy up now-empty per-application and per-user directories; failures are harmless.
+	for dir := filepath.Dir(p); dir != l.root && strings.HasPrefix(dir, l.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
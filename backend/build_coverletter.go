@@ -9,7 +9,9 @@ import (
 // Placeholder for future cover letter PDF generation helpers.
 // This file is intentionally minimal so it doesn't affect the build until implemented.
 
-func generateCoverLetterLatex(resume ResumeData, cl *CoverLetter) string {
+// generateCoverLetterLatex renders the cover letter. contact is the application's primary
+// contact (may be nil); it fills in the recipient and greeting when the letter leaves them blank.
+func generateCoverLetterLatex(resume ResumeData, cl *CoverLetter, contact *Contact) string {
 
 	// intended layout of cover letter:
 	// 1. CoverLetterHead
@@ -25,8 +27,8 @@ func generateCoverLetterLatex(resume ResumeData, cl *CoverLetter) string {
 	var out strings.Builder
 	out.WriteString(generateCoverLetterHead())
 	out.WriteString(generateCoverLetterApplicantHeader(resume))
-	out.WriteString(generateCoverLetterAdress(cl))
-	out.WriteString(generateCoverLetterGreeting(cl))
+	out.WriteString(generateCoverLetterAdress(cl, contact))
+	out.WriteString(generateCoverLetterGreeting(cl, contact))
 	out.WriteString(generateCoverLetterParagraphs(cl))
 	out.WriteString(generateCoverLetterClosing(cl))
 	return out.String()
@@ -55,7 +57,7 @@ func generateCoverLetterApplicantHeader(data ResumeData) string {
 	return header + "\n\\vspace{18pt}\n"
 }
 
-func generateCoverLetterAdress(cl *CoverLetter) string {
+func generateCoverLetterAdress(cl *CoverLetter, contact *Contact) string {
 	if cl == nil {
 		return ""
 	}
@@ -63,12 +65,25 @@ func generateCoverLetterAdress(cl *CoverLetter) string {
 	manager := strings.TrimSpace(cl.HiringManagerName)
 	company := strings.TrimSpace(cl.Company)
 	location := strings.TrimSpace(cl.Location)
+	role := ""
+	if contact != nil {
+		if manager == "" {
+			manager = contact.Name
+			role = contact.Role
+		}
+		if company == "" {
+			company = contact.Company
+		}
+	}
 
-	lines := make([]string, 0, 4)
+	lines := make([]string, 0, 5)
 	lines = append(lines, fmt.Sprintf("\\noindent %s\\\\", time.Now().Format("January 2, 2006")))
 	if manager != "" {
 		lines = append(lines, fmt.Sprintf("%s\\\\", esc(manager)))
 	}
+	if role != "" {
+		lines = append(lines, fmt.Sprintf("%s\\\\", esc(role)))
+	}
 	if company != "" {
 		lines = append(lines, fmt.Sprintf("%s\\\\", esc(company)))
 	}
@@ -79,15 +94,19 @@ func generateCoverLetterAdress(cl *CoverLetter) string {
 	return "\n" + strings.Join(lines, "\n") + "\n \\vspace{40pt}\n"
 }
 
-func generateCoverLetterGreeting(cl *CoverLetter) string {
+func generateCoverLetterGreeting(cl *CoverLetter, contact *Contact) string {
 	if cl == nil {
 		return ""
 	}
 
 	greeting := strings.TrimSpace(cl.Greeting)
 	if greeting == "" {
-		if strings.TrimSpace(cl.HiringManagerName) != "" {
-			greeting = fmt.Sprintf("Dear %s,", strings.TrimSpace(cl.HiringManagerName))
+		name := strings.TrimSpace(cl.HiringManagerName)
+		if name == "" && contact != nil {
+			name = strings.TrimSpace(contact.Name)
+		}
+		if name != "" {
+			greeting = fmt.Sprintf("Dear %s,", name)
 		} else {
 			greeting = "Dear Hiring Manager,"
 		}
@@ -3,39 +3,73 @@ package main
 import (
 	"archive/zip"
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
+// handleGeneratePDF renders the posted application's resume and cover letter and returns them
+// as a ZIP. With ?save=1 the PDFs and the input are also kept against the saved application
+// (see handleListRenders) and the response carries the new render's id in X-Render-Id.
 func handleGeneratePDF(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+
+	var save bool
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("save"))) {
+	case "", "0", "false":
+	case "1", "true":
+		save = true
+	default:
+		writeError(w, r, badRequest("invalid save (use save=1 or save=0)"))
 		return
 	}
 
 	latexPath, err := exec.LookPath("pdflatex")
 	if err != nil {
-		http.Error(w, "pdflatex not found in PATH; please install TeX Live (package name on Arch/Manjaro: texlive-bin) and ensure pdflatex is available", http.StatusInternalServerError)
+		writeError(w, r, rendererUnavailable("pdflatex not found in PATH; please install TeX Live (package name on Arch/Manjaro: texlive-bin) and ensure pdflatex is available"))
 		return
 	}
 
 	var app Application
-	err2 := json.NewDecoder(r.Body).Decode(&app)
-	if err2 != nil {
-		http.Error(w, err2.Error(), http.StatusBadRequest)
+	if err := decodeJSONBody(w, r, &app); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateApplication(app); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	resumePDF, coverPDF, err := generateResumeAndCoverPDFs(latexPath, app)
+	// Look the application up before compiling so an unsaved one fails fast.
+	var (
+		s      Store
+		userID string
+		stored Application
+	)
+	if save {
+		if userID, err = userIDFromRequest(r); err != nil {
+			writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, err.Error()))
+			return
+		}
+		if s = currentStore(); s == nil {
+			writeError(w, r, errStoreUnavailable)
+			return
+		}
+		if stored, err = s.GetApplication(r.Context(), userID, strings.TrimSpace(app.ID)); err != nil {
+			writeError(w, r, orNotFound(err, "Application not found; save it before keeping its PDFs"))
+			return
+		}
+	}
+
+	contact := primaryContactFor(r, app)
+	resumePDF, coverPDF, err := generateResumeAndCoverPDFs(r.Context(), latexPath, app, contact)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, renderFailed(err))
 		return
 	}
 
@@ -44,48 +78,61 @@ func handleGeneratePDF(w http.ResponseWriter, r *http.Request) {
 	coverFilename := fmt.Sprintf("%s_Cover_Letter.pdf", namePart)
 	zipBytes, err := zipDocuments(resumePDF, coverPDF, resumeFilename, coverFilename)
 	if err != nil {
-		http.Error(w, "Failed to package PDFs: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	positionPart := sanitizeFilePart(app.JobTitle, "position")
-	companyPart := sanitizeFilePart(app.Company, "company")
-	zipFilename := fmt.Sprintf("%s_%s.zip", positionPart, companyPart)
+	if save {
+		render, err := saveRender(r.Context(), s, userID, stored, renderInput{Application: app, Contact: contact}, resumePDF, coverPDF, resumeFilename, coverFilename)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Set("X-Render-Id", render.ID)
+	}
+
 	w.Header().Set("Content-Type", "application/zip")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", zipFilename))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pdfZipFilename(app)))
 	w.Write(zipBytes)
 }
 
+// pdfZipFilename names the ZIP of an application's PDFs after its position and company.
+func pdfZipFilename(app Application) string {
+	positionPart := sanitizeFilePart(app.JobTitle, "position")
+	companyPart := sanitizeFilePart(app.Company, "company")
+	return fmt.Sprintf("%s_%s.zip", positionPart, companyPart)
+}
+
 func handlePreviewPDF(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
-		return
-	}
 
 	doc := strings.TrimSpace(strings.ToLower(r.URL.Query().Get("doc")))
 	if doc == "" {
 		doc = "resume"
 	}
 	if doc != "resume" && doc != "cover" && doc != "cover_letter" && doc != "coverletter" {
-		http.Error(w, "invalid doc (use doc=resume or doc=cover)", http.StatusBadRequest)
+		writeError(w, r, badRequest("invalid doc (use doc=resume or doc=cover)"))
 		return
 	}
 
 	latexPath, err := exec.LookPath("pdflatex")
 	if err != nil {
-		http.Error(w, "pdflatex not found in PATH; please install TeX Live and ensure pdflatex is available", http.StatusInternalServerError)
+		writeError(w, r, rendererUnavailable("pdflatex not found in PATH; please install TeX Live and ensure pdflatex is available"))
 		return
 	}
 
 	var app Application
-	if err := json.NewDecoder(r.Body).Decode(&app); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeJSONBody(w, r, &app); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateApplication(app); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	pdfBytes, filename, err := generateSinglePDF(latexPath, app, doc)
+	pdfBytes, filename, err := generateSinglePDF(r.Context(), latexPath, app, doc, primaryContactFor(r, app))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, renderFailed(err))
 		return
 	}
 
@@ -95,13 +142,19 @@ func handlePreviewPDF(w http.ResponseWriter, r *http.Request) {
 	w.Write(pdfBytes)
 }
 
-func compileLatexToPDF(latexPath, tmpDir, texPath string) error {
-	cmd := exec.Command(latexPath, "-interaction=nonstopmode", "-halt-on-error", "-output-directory="+tmpDir, texPath)
+// compileLatexToPDF runs pdflatex on texPath, writing the PDF into tmpDir. The compile is
+// killed if ctx is cancelled (the client went away or the server is shutting down).
+func compileLatexToPDF(ctx context.Context, latexPath, tmpDir, texPath string) error {
+	logger := loggerFrom(ctx).With("tex", filepath.Base(texPath))
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, latexPath, "-interaction=nonstopmode", "-halt-on-error", "-output-directory="+tmpDir, texPath)
 	output, err := cmd.CombinedOutput()
+	duration := time.Since(start)
 	if err != nil {
-		log.Printf("pdflatex compilation failed: %v\nOutput:\n%s", err, string(output))
+		logger.Warn("pdflatex compilation failed", "err", err, "durationMs", duration.Milliseconds(), "output", string(output))
 		return fmt.Errorf("pdflatex compilation failed: %v\n%s", err, string(output))
 	}
+	logger.Debug("pdflatex compiled", "durationMs", duration.Milliseconds())
 	return nil
 }
 
@@ -135,13 +188,13 @@ func zipDocuments(resumePDF, coverPDF []byte, resumeName, coverName string) ([]b
 	return buf.Bytes(), nil
 }
 
-func generateResumeAndCoverPDFs(latexPath string, app Application) ([]byte, []byte, error) {
+func generateResumeAndCoverPDFs(ctx context.Context, latexPath string, app Application, contact *Contact) ([]byte, []byte, error) {
 	latexContent, err := generateLatexContent(app.Resume)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	coverLetterLatex := generateCoverLetterLatex(app.Resume, app.CoverLetter)
+	coverLetterLatex := generateCoverLetterLatex(app.Resume, app.CoverLetter, contact)
 	if strings.TrimSpace(coverLetterLatex) == "" {
 		// Always generate a cover letter PDF; if missing content, emit an empty document.
 		head, _ := readTemplate("coverletter_head.tex")
@@ -167,10 +220,10 @@ func generateResumeAndCoverPDFs(latexPath string, app Application) ([]byte, []by
 		return nil, nil, err
 	}
 
-	if err := compileLatexToPDF(latexPath, tmpDir, resumeTexPath); err != nil {
+	if err := compileLatexToPDF(ctx, latexPath, tmpDir, resumeTexPath); err != nil {
 		return nil, nil, err
 	}
-	if err := compileLatexToPDF(latexPath, tmpDir, coverTexPath); err != nil {
+	if err := compileLatexToPDF(ctx, latexPath, tmpDir, coverTexPath); err != nil {
 		return nil, nil, err
 	}
 
@@ -186,7 +239,7 @@ func generateResumeAndCoverPDFs(latexPath string, app Application) ([]byte, []by
 	return resumePDF, coverPDF, nil
 }
 
-func generateSinglePDF(latexPath string, app Application, doc string) ([]byte, string, error) {
+func generateSinglePDF(ctx context.Context, latexPath string, app Application, doc string, contact *Contact) ([]byte, string, error) {
 	tmpDir, err := ioutil.TempDir("", "resume-latex")
 	if err != nil {
 		return nil, "", fmt.Errorf("Failed to create temp directory: %w", err)
@@ -208,7 +261,7 @@ func generateSinglePDF(latexPath string, app Application, doc string) ([]byte, s
 		if err := ioutil.WriteFile(resumeTexPath, []byte(latexContent), 0644); err != nil {
 			return nil, "", fmt.Errorf("Failed to write resume .tex file: %w", err)
 		}
-		if err := compileLatexToPDF(latexPath, tmpDir, resumeTexPath); err != nil {
+		if err := compileLatexToPDF(ctx, latexPath, tmpDir, resumeTexPath); err != nil {
 			return nil, "", err
 		}
 		pdfBytes, err := ioutil.ReadFile(filepath.Join(tmpDir, "resume.pdf"))
@@ -219,7 +272,7 @@ func generateSinglePDF(latexPath string, app Application, doc string) ([]byte, s
 
 	default:
 		namePart := sanitizeFilePart(app.Resume.Name, "Resume")
-		coverLetterLatex := generateCoverLetterLatex(app.Resume, app.CoverLetter)
+		coverLetterLatex := generateCoverLetterLatex(app.Resume, app.CoverLetter, contact)
 		if strings.TrimSpace(coverLetterLatex) == "" {
 			head, _ := readTemplate("coverletter_head.tex")
 			coverLetterLatex = head + "\\end{document}\n"
@@ -228,7 +281,7 @@ func generateSinglePDF(latexPath string, app Application, doc string) ([]byte, s
 		if err := ioutil.WriteFile(coverTexPath, []byte(coverLetterLatex), 0644); err != nil {
 			return nil, "", fmt.Errorf("Failed to write cover letter .tex file: %w", err)
 		}
-		if err := compileLatexToPDF(latexPath, tmpDir, coverTexPath); err != nil {
+		if err := compileLatexToPDF(ctx, latexPath, tmpDir, coverTexPath); err != nil {
 			return nil, "", err
 		}
 		pdfBytes, err := ioutil.ReadFile(filepath.Join(tmpDir, "cover_letter.pdf"))
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"sort"
 	"strings"
@@ -41,15 +40,17 @@ func getReposJson(ctx context.Context, geminiAPIKey string, username string, inc
 	ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
 	defer cancel()
 
+	logger := loggerFrom(ctx).With("githubUser", username)
 	client := newGitHubClient()
 	cards, err := getPublicRepos(ctx, client, username)
 	if err != nil {
+		logger.Warn("list github repos failed", "err", err)
 		return []ProjectCard{}
 	}
+	logger.Debug("listed github repos", "count", len(cards))
 
 	// Best-effort enrichment: keep the repo even if README/languages/AI fail.
 	for i := range cards {
-		fmt.Println(cards[i].Repo)
 		if cards[i].Owner == "" || cards[i].Repo == "" {
 			// Keep the card but skip lookups we can't perform.
 			continue
@@ -71,7 +72,7 @@ func getReposJson(ctx context.Context, geminiAPIKey string, username string, inc
 				cards[i].Points = points
 			}
 		} else {
-			log.Printf("consultAI failed for %s/%s: %v", cards[i].Owner, cards[i].Repo, err)
+			logger.Warn("project points generation failed", "repo", cards[i].Owner+"/"+cards[i].Repo, "err", err)
 			if includeAIErrors {
 				cards[i].AIError = err.Error()
 			}
@@ -82,7 +83,6 @@ func getReposJson(ctx context.Context, geminiAPIKey string, username string, inc
 }
 
 func getPublicRepos(ctx context.Context, client *github.Client, username string) ([]ProjectCard, error) {
-	fmt.Println("getting repos")
 	if username == "" {
 		return nil, fmt.Errorf("username is required")
 	}
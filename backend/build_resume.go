@@ -29,26 +29,74 @@ func generateObjective(data ResumeData) (string, error) {
 }
 
 func generateEducation(data ResumeData) (string, error) {
+	if len(data.Education) == 0 && len(data.RelevantCourses) == 0 {
+		return "", nil
+	}
+
 	educationTemplate, err := readTemplate("resume_education.tex")
 	if err != nil {
 		return "", err
 	}
 
-	var courseList []string
+	var entries bytes.Buffer
+	for _, entry := range data.Education {
+		degree := entry.Degree
+		if entry.Field != "" {
+			if degree != "" {
+				degree += ", "
+			}
+			degree += entry.Field
+		}
 
-	for i, course := range data.RelevantCourses {
-		courseList = append(courseList, esc(course))
-		if i < len(data.RelevantCourses)-1 {
-			courseList = append(courseList, ", ")
+		entries.WriteString("\\resumeSubheading \n {")
+		entries.WriteString(esc(entry.Institution))
+		entries.WriteString("}{")
+		entries.WriteString(esc(dateRange(entry.StartDate, entry.EndDate)))
+		entries.WriteString("}{")
+		entries.WriteString(esc(degree))
+		entries.WriteString("}{")
+		entries.WriteString(esc(entry.Location))
+		entries.WriteString("} \n")
+
+		if entry.GPA != "" {
+			entries.WriteString("\\item \\small{\\textbf{GPA:} ")
+			entries.WriteString(esc(entry.GPA))
+			entries.WriteString("} \n")
+		}
+		if len(entry.Courses) > 0 {
+			entries.WriteString(relevantCoursesItem(entry.Courses))
 		}
 	}
+	if len(data.RelevantCourses) > 0 {
+		entries.WriteString(relevantCoursesItem(data.RelevantCourses))
+	}
 
-	courses := strings.Join(courseList, "")
-
-	education := educationTemplate + courses + "} \n \\resumeSubHeadingListEnd"
+	education := educationTemplate + entries.String() + "\\resumeSubHeadingListEnd \n"
 	return education, nil
 }
 
+func relevantCoursesItem(courses []string) string {
+	escaped := make([]string, 0, len(courses))
+	for _, course := range courses {
+		escaped = append(escaped, esc(course))
+	}
+	return "\\item \\small{\\textbf{Relevant Courses:} " + strings.Join(escaped, ", ") + "} \n"
+}
+
+// dateRange joins a start and end date with an en dash, tolerating either being blank.
+func dateRange(start, end string) string {
+	start = strings.TrimSpace(start)
+	end = strings.TrimSpace(end)
+	switch {
+	case start == "":
+		return end
+	case end == "":
+		return start
+	default:
+		return start + " -- " + end
+	}
+}
+
 func generateProjects(data ResumeData) (string, error) {
 	projectTemplate, err := readTemplate("resume_projects.tex")
 	if err != nil {
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// iCalendar (RFC 5545) export of interview rounds and apply-by deadlines.
+
+const (
+	icsProdID           = "-//Job Application Central//Applications//EN"
+	icsUIDDomain        = "job-app-central"
+	icsTimeLayout       = "20060102T150405Z"
+	icsDateLayout       = "20060102"
+	icsInterviewLength  = time.Hour
+	icsMaxLineOctets    = 75
+	calendarTokenPrefix = "cal_"
+)
+
+// icsEvent is one VEVENT. AllDay events use Start's date only.
+type icsEvent struct {
+	UID           string
+	Start         time.Time
+	AllDay        bool
+	Duration      time.Duration
+	Summary       string
+	Description   string
+	ApplicationID string
+}
+
+// calendarEvents turns application schedules into events: one per interview round and one
+// all-day event per deadline of an application that hasn't been applied to yet.
+func calendarEvents(schedules []ApplicationSchedule) []icsEvent {
+	var events []icsEvent
+	for _, s := range schedules {
+		what := strings.TrimSpace(s.JobTitle)
+		if company := strings.TrimSpace(s.Company); company != "" {
+			what += " at " + company
+		}
+
+		for _, round := range s.Interviews {
+			var desc []string
+			desc = append(desc, "Company: "+s.Company, "Job title: "+s.JobTitle, "Format: "+round.Format)
+			if round.Interviewer != "" {
+				desc = append(desc, "Interviewer: "+round.Interviewer)
+			}
+			if round.Notes != "" {
+				desc = append(desc, "Notes: "+round.Notes)
+			}
+			desc = append(desc, "Application ID: "+s.ID)
+			events = append(events, icsEvent{
+				UID:           fmt.Sprintf("%s-interview-%d@%s", s.ID, round.ScheduledAt.Unix(), icsUIDDomain),
+				Start:         round.ScheduledAt,
+				Duration:      icsInterviewLength,
+				Summary:       "Interview: " + what,
+				Description:   strings.Join(desc, "\n"),
+				ApplicationID: s.ID,
+			})
+		}
+
+		if s.ApplicationStatus == statusSaved && s.Deadline != "" {
+			d, err := time.Parse(dateLayout, s.Deadline)
+			if err != nil {
+				continue
+			}
+			events = append(events, icsEvent{
+				UID:           fmt.Sprintf("%s-deadline@%s", s.ID, icsUIDDomain),
+				Start:         d,
+				AllDay:        true,
+				Summary:       "Apply by: " + what,
+				Description:   strings.Join([]string{"Company: " + s.Company, "Job title: " + s.JobTitle, "Application ID: " + s.ID}, "\n"),
+				ApplicationID: s.ID,
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+// buildICS renders a VCALENDAR with CRLF line endings and folded content lines.
+func buildICS(name string, events []icsEvent, now time.Time) []byte {
+	var b strings.Builder
+	line := func(s string) { writeICSLine(&b, s) }
+
+	stamp := now.UTC().Format(icsTimeLayout)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + icsProdID)
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICSText(name))
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp)
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE:" + e.Start.Format(icsDateLayout))
+			line("DTEND;VALUE=DATE:" + e.Start.AddDate(0, 0, 1).Format(icsDateLayout))
+		} else {
+			line("DTSTART:" + e.Start.UTC().Format(icsTimeLayout))
+			line("DTEND:" + e.Start.Add(e.Duration).UTC().Format(icsTimeLayout))
+		}
+		line("SUMMARY:" + escapeICSText(e.Summary))
+		line("DESCRIPTION:" + escapeICSText(e.Description))
+		line("X-JOBAPP-APPLICATION-ID:" + e.ApplicationID)
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escapeICSText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeICSText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// writeICSLine writes one content line, folding it at 75 octets without splitting UTF-8 sequences.
+func writeICSLine(b *strings.Builder, s string) {
+	limit := icsMaxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = icsMaxLineOctets - 1 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+func isUTF8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+// newCalendarToken returns a random feed token and the hash that is stored for it.
+func newCalendarToken() (string, []byte, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := calendarTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashCalendarToken(token), nil
+}
+
+func hashCalendarToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
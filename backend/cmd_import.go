@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"time"
+)
+
+// runImportLegacyCommand implements `import-legacy -user <uuid> [paths...]`, importing legacy
+// application/resume JSON files straight into the database for one user.
+func runImportLegacyCommand(args []string) error {
+	fset := flag.NewFlagSet("import-legacy", flag.ContinueOnError)
+	userID := fset.String("user", "", "Supabase user id (uuid) to import into")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*userID) == "" {
+		return errors.New("usage: jobapp-backend import-legacy -user <uuid> [paths...]")
+	}
+	paths := fset.Args()
+	if len(paths) == 0 {
+		paths = []string{"applications", "data/resume_data.json"}
+	}
+
+	files, err := readLegacyPaths(paths)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	s, err := newDBStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	result, err := importLegacyFiles(ctx, s, strings.TrimSpace(*userID), files)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(result)
+	return err
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// runOpenAPICommand implements `openapi [-o openapi.json] [-client client/jobapp.ts]`,
+// regenerating the OpenAPI document and the TypeScript client from the Go types.
+func runOpenAPICommand(args []string) error {
+	fset := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	specPath := fset.String("o", "openapi.json", "where to write the OpenAPI document")
+	clientPath := fset.String("client", filepath.Join("client", "jobapp.ts"), "where to write the TypeScript client (empty to skip)")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := openAPIJSON()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*specPath, spec, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", *specPath)
+
+	if *clientPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(*clientPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*clientPath, typeScriptClient(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", *clientPath)
+	return nil
+}
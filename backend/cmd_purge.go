@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// runPurgeTrashCommand implements `purge-trash [-days N]`, permanently deleting applications
+// that have been in the trash longer than N days (TRASH_RETENTION_DAYS by default). With
+// TRASH_RETENTION_DAYS=0 purging is disabled, so -days must be given explicitly.
+func runPurgeTrashCommand(args []string) error {
+	retention, err := trashRetentionFromEnv()
+	if err != nil {
+		return err
+	}
+
+	fset := flag.NewFlagSet("purge-trash", flag.ContinueOnError)
+	days := fset.Int("days", int(retention/(24*time.Hour)), "purge applications trashed more than this many days ago")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if *days < 0 {
+		return errors.New("usage: jobapp-backend purge-trash [-days N]")
+	}
+	daysSet := false
+	fset.Visit(func(f *flag.Flag) { daysSet = daysSet || f.Name == "days" })
+	if retention == 0 && !daysSet {
+		return errors.New("trash purging is disabled (TRASH_RETENTION_DAYS=0); pass -days N to purge anyway")
+	}
+
+	b, err := newBlobStoreFromEnv()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	s, err := newDBStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	n, err := purgeTrash(ctx, s, b, time.Duration(*days)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("purged %d application(s)\n", n)
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errInvalidContact = errors.New("invalid contact")
+
+// LinkContact reports which side of a link is missing; both are errNotFound.
+var (
+	errLinkApplicationNotFound = fmt.Errorf("%w: application", errNotFound)
+	errLinkContactNotFound     = fmt.Errorf("%w: contact", errNotFound)
+)
+
+// Contact is a recruiter, hiring manager or other person tied to one or more applications.
+type Contact struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	LinkedIn  string    `json:"linkedin"`
+	Company   string    `json:"company"`
+	Role      string    `json:"role"`
+	Notes     string    `json:"notes"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LinkedContact is a contact as seen from one application.
+type LinkedContact struct {
+	Contact
+	Primary bool `json:"primary"`
+}
+
+// normalizeContact trims a contact's fields and checks that it has a name and a plausible email.
+func normalizeContact(c *Contact) error {
+	for _, f := range []*string{&c.Name, &c.Email, &c.Phone, &c.LinkedIn, &c.Company, &c.Role} {
+		*f = strings.TrimSpace(*f)
+	}
+	if c.Name == "" {
+		return fmt.Errorf("%w: name is required", errInvalidContact)
+	}
+	if c.Email != "" && (!strings.Contains(c.Email, "@") || strings.ContainsAny(c.Email, " \t\r\n")) {
+		return fmt.Errorf("%w: email %q is not valid", errInvalidContact, c.Email)
+	}
+	return nil
+}
@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,7 +24,30 @@ func envBool(name string) bool {
 	return v == "1" || v == "true" || v == "yes" || v == "on"
 }
 
+// newDBStore connects to the database and applies any pending migrations.
+// Set DB_SKIP_MIGRATIONS=1 to connect without migrating.
 func newDBStore(ctx context.Context) (*dbStore, error) {
+	s, err := openDBStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if envBool("DB_SKIP_MIGRATIONS") {
+		return s, nil
+	}
+
+	applied, err := migrateUp(ctx, s.pool)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	for _, v := range applied {
+		loggerFrom(ctx).Info("applied migration", "version", v)
+	}
+	return s, nil
+}
+
+// openDBStore connects to DATABASE_URL without touching the schema.
+func openDBStore(ctx context.Context) (*dbStore, error) {
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
 		return nil, fmt.Errorf("DATABASE_URL not set")
@@ -35,6 +61,7 @@ func newDBStore(ctx context.Context) (*dbStore, error) {
 	cfg.MinConns = 0
 	cfg.MaxConnLifetime = 30 * time.Minute
 	cfg.MaxConnIdleTime = 5 * time.Minute
+	cfg.ConnConfig.Tracer = dbQueryTracer{}
 
 	// Some hosts/environments (e.g. Railway/Render) don't have IPv6 egress.
 	// Supabase free-tier direct DB hostname may be IPv6-only, so optionally force IPv4 resolution/dialing.
@@ -85,6 +112,85 @@ func newDBStore(ctx context.Context) (*dbStore, error) {
 	return &dbStore{pool: pool}, nil
 }
 
+// dbReconnectInterval is how often runDBConnector retries while the database is unreachable.
+const dbReconnectInterval = 5 * time.Second
+
+// runDBConnector connects to the database in the background until it succeeds, so the server
+// can start (and answer 503s) while the database is unreachable. It returns once the store is
+// set or ctx is cancelled.
+func runDBConnector(ctx context.Context) {
+	t := time.NewTicker(dbReconnectInterval)
+	defer t.Stop()
+	for currentStore() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		s, err := newDBStore(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("DB still not ready", "err", err)
+			}
+			continue
+		}
+		storeMu.Lock()
+		// Double-check; another goroutine may have set it.
+		if store == nil {
+			store = s
+			s = nil
+			slog.Info("DB connection established")
+		}
+		storeMu.Unlock()
+		if s != nil {
+			s.Close()
+		}
+	}
+}
+
+const (
+	ctxQueryTrace ctxKey = "dbQueryTrace"
+
+	// dbSlowQuery is how long a query may take before it is logged as slow.
+	dbSlowQuery = 500 * time.Millisecond
+	// maxLoggedSQL truncates logged statements; a few are long multi-line queries.
+	maxLoggedSQL = 300
+)
+
+// dbQueryTracer logs queries on the logger of the context they run in, so a failing or slow
+// query can be tied to its request and user. Query arguments hold user data and are never logged.
+type dbQueryTracer struct{}
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+}
+
+func (dbQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, ctxQueryTrace, queryTrace{sql: data.SQL, start: time.Now()})
+}
+
+func (dbQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(ctxQueryTrace).(queryTrace)
+	if !ok {
+		return
+	}
+	duration := time.Since(trace.start)
+	sql := strings.Join(strings.Fields(trace.sql), " ")
+	if len(sql) > maxLoggedSQL {
+		sql = sql[:maxLoggedSQL] + "..."
+	}
+	logger := loggerFrom(ctx)
+	switch {
+	case data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) && !errors.Is(data.Err, context.Canceled):
+		logger.Warn("db query failed", "sql", sql, "durationMs", duration.Milliseconds(), "err", data.Err)
+	case duration >= dbSlowQuery:
+		logger.Warn("slow db query", "sql", sql, "durationMs", duration.Milliseconds())
+	default:
+		logger.Debug("db query", "sql", sql, "durationMs", duration.Milliseconds(), "rows", data.CommandTag.RowsAffected())
+	}
+}
+
 func (s *dbStore) Close() {
 	if s != nil && s.pool != nil {
 		s.pool.Close()
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errVersionConflict      = errors.New("resource was modified by another request")
+	errPreconditionRequired = errors.New("If-Match header is required")
+)
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion parses the If-Match header into the version the client last saw.
+// Profiles that don't exist yet may be created with If-None-Match: * instead, reported as version 0.
+func ifMatchVersion(r *http.Request, allowCreate bool) (int64, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" {
+		if allowCreate && strings.TrimSpace(r.Header.Get("If-None-Match")) == "*" {
+			return 0, nil
+		}
+		return 0, errPreconditionRequired
+	}
+	raw = strings.TrimPrefix(raw, "W/")
+	raw = strings.Trim(raw, `"`)
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v < 1 {
+		return 0, badRequest(fmt.Sprintf("invalid If-Match value %q", r.Header.Get("If-Match")))
+	}
+	return v, nil
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// An account export is a ZIP archive:
+//
+//	manifest.json                 exportManifest
+//	profile.json                  the profile, if the user has one
+//	applications/<id>.json        every live Application
+//	pdfs/<id>/<Name>_Resume.pdf   rendered PDFs, when requested
+//	pdfs/<id>/<Name>_Cover_Letter.pdf
+//
+// Importing reads the manifest, profile and applications and ignores the PDFs.
+
+const (
+	exportFormat        = "jobapp-export"
+	exportFormatVersion = 1
+
+	maxExportUploadSize = 64 << 20
+	maxExportFileSize   = 5 << 20
+)
+
+var errInvalidExport = errors.New("invalid export archive")
+
+type exportManifest struct {
+	Format       string    `json:"format"`
+	Version      int       `json:"version"`
+	ExportedAt   time.Time `json:"exportedAt"`
+	Profile      bool      `json:"profile"`
+	Applications int       `json:"applications"`
+	PDFs         bool      `json:"pdfs"`
+}
+
+// exportPDFRenderer renders an application's resume and cover letter; nil skips PDFs.
+type exportPDFRenderer func(app Application) (resumePDF, coverPDF []byte, err error)
+
+// writeAccountExport writes userID's profile and live applications to w as a ZIP archive.
+func writeAccountExport(ctx context.Context, w io.Writer, s Store, userID string, render exportPDFRenderer) error {
+	profile, _, err := s.GetProfile(ctx, userID)
+	if isNotFound(err) {
+		profile = nil
+	} else if err != nil {
+		return fmt.Errorf("load profile: %w", err)
+	}
+
+	var apps []Application
+	q := ApplicationListQuery{Sort: sortCreated, Limit: maxApplicationPageSize}
+	for {
+		page, err := s.ListApplicationSummaries(ctx, userID, q)
+		if err != nil {
+			return fmt.Errorf("list applications: %w", err)
+		}
+		for _, summary := range page.Items {
+			app, err := s.GetApplication(ctx, userID, summary.ID)
+			if isNotFound(err) {
+				continue // trashed since it was listed
+			}
+			if err != nil {
+				return fmt.Errorf("load application %s: %w", summary.ID, err)
+			}
+			apps = append(apps, app)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if q.After, err = decodeListCursor(page.NextCursor); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	zw := zip.NewWriter(w)
+	create := func(name string) (io.Writer, error) {
+		return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+	}
+	writeJSON := func(name string, v any) error {
+		f, err := create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	manifest := exportManifest{
+		Format:       exportFormat,
+		Version:      exportFormatVersion,
+		ExportedAt:   now,
+		Profile:      len(profile) > 0,
+		Applications: len(apps),
+		PDFs:         render != nil,
+	}
+	if err := writeJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+	if len(profile) > 0 {
+		if err := writeJSON("profile.json", profile); err != nil {
+			return err
+		}
+	}
+	for _, app := range apps {
+		if err := writeJSON("applications/"+app.ID+".json", app); err != nil {
+			return err
+		}
+	}
+	if render != nil {
+		for _, app := range apps {
+			resumePDF, coverPDF, err := render(app)
+			if err != nil {
+				return fmt.Errorf("render application %s: %w", app.ID, err)
+			}
+			namePart := sanitizeFilePart(app.Resume.Name, "Resume")
+			for _, doc := range []struct {
+				name string
+				data []byte
+			}{{namePart + "_Resume.pdf", resumePDF}, {namePart + "_Cover_Letter.pdf", coverPDF}} {
+				f, err := create("pdfs/" + app.ID + "/" + doc.name)
+				if err != nil {
+					return err
+				}
+				if _, err := f.Write(doc.data); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return zw.Close()
+}
+
+// accountArchive is the parsed content of an export archive.
+type accountArchive struct {
+	Manifest     exportManifest
+	Profile      json.RawMessage
+	Applications []legacyFile // applications/<id>.json entries, in name order
+}
+
+// readAccountArchive parses an export archive, rejecting anything without a jobapp manifest.
+func readAccountArchive(data []byte) (accountArchive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return accountArchive{}, fmt.Errorf("%w: %v", errInvalidExport, err)
+	}
+
+	var out accountArchive
+	var haveManifest bool
+	for _, f := range zr.File {
+		name := path.Clean(f.Name)
+		isApplication := path.Dir(name) == "applications" && strings.HasSuffix(name, ".json")
+		if f.FileInfo().IsDir() || (name != "manifest.json" && name != "profile.json" && !isApplication) {
+			continue
+		}
+		if f.UncompressedSize64 > maxExportFileSize {
+			return accountArchive{}, fmt.Errorf("%w: %s exceeds %d bytes", errInvalidExport, f.Name, maxExportFileSize)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return accountArchive{}, fmt.Errorf("%w: %v", errInvalidExport, err)
+		}
+		body, err := io.ReadAll(io.LimitReader(rc, maxExportFileSize+1))
+		rc.Close()
+		if err != nil {
+			return accountArchive{}, fmt.Errorf("%w: %s: %v", errInvalidExport, f.Name, err)
+		}
+
+		switch {
+		case name == "manifest.json":
+			if err := json.Unmarshal(body, &out.Manifest); err != nil {
+				return accountArchive{}, fmt.Errorf("%w: manifest.json: %v", errInvalidExport, err)
+			}
+			haveManifest = true
+		case name == "profile.json":
+			if !json.Valid(body) {
+				return accountArchive{}, fmt.Errorf("%w: profile.json is not valid JSON", errInvalidExport)
+			}
+			out.Profile = body
+		default:
+			out.Applications = append(out.Applications, legacyFile{Name: name, Data: body})
+		}
+	}
+
+	if !haveManifest || out.Manifest.Format != exportFormat {
+		return accountArchive{}, fmt.Errorf("%w: manifest.json is missing or not a %s archive (legacy files go to /api/import/legacy)", errInvalidExport, exportFormat)
+	}
+	if out.Manifest.Version < 1 || out.Manifest.Version > exportFormatVersion {
+		return accountArchive{}, fmt.Errorf("%w: unsupported export version %d", errInvalidExport, out.Manifest.Version)
+	}
+	sort.Slice(out.Applications, func(i, j int) bool { return out.Applications[i].Name < out.Applications[j].Name })
+	return out, nil
+}
+
+// Profile outcomes of an account import.
+const (
+	profileAbsent    = "absent"
+	profileCreated   = "created"
+	profileUpdated   = "updated"
+	profileUnchanged = "unchanged"
+)
+
+// AccountImportResult reports what an account import did with the profile and each application.
+type AccountImportResult struct {
+	Profile string `json:"profile"`
+	ImportResult
+}
+
+// importAccountArchive restores an export into userID's account. Applications keep their ids,
+// so ones that already exist are skipped and importing the same archive twice changes nothing.
+// Applications that fail the same validation as a save are skipped.
+// The profile replaces the current one when it differs.
+func importAccountArchive(ctx context.Context, s Store, userID string, archive accountArchive) (AccountImportResult, error) {
+	result := AccountImportResult{
+		Profile:      profileAbsent,
+		ImportResult: ImportResult{Imported: []ImportedItem{}, Skipped: []SkippedItem{}},
+	}
+
+	if len(archive.Profile) > 0 {
+		current, version, err := s.GetProfile(ctx, userID)
+		switch {
+		case isNotFound(err):
+			version = 0
+			result.Profile = profileCreated
+		case err != nil:
+			return result, fmt.Errorf("load profile: %w", err)
+		case jsonEqual(current, archive.Profile):
+			result.Profile = profileUnchanged
+		default:
+			result.Profile = profileUpdated
+		}
+		if result.Profile != profileUnchanged {
+			if _, err := s.UpsertProfile(ctx, userID, archive.Profile, version); err != nil {
+				return result, fmt.Errorf("save profile: %w", err)
+			}
+		}
+	}
+
+	for _, f := range archive.Applications {
+		var app Application
+		if err := json.Unmarshal(f.Data, &app); err != nil {
+			result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: "invalid JSON: " + err.Error()})
+			continue
+		}
+		if _, err := uuid.Parse(app.ID); err != nil {
+			result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: "missing or invalid id"})
+			continue
+		}
+		if err := validateApplication(app); err != nil {
+			var apiErr *apiError
+			if errors.As(err, &apiErr) {
+				result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: apiErr.Message})
+				continue
+			}
+			return result, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		saved, created, err := s.ImportApplication(ctx, userID, app)
+		if err != nil {
+			if errors.Is(err, errInvalidStatus) || errors.Is(err, errInvalidSchedule) || errors.Is(err, errInvalidTag) {
+				result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: err.Error()})
+				continue
+			}
+			return result, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if !created {
+			result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: "already imported as " + saved.ID})
+			continue
+		}
+		result.Imported = append(result.Imported, ImportedItem{
+			Source:   f.Name,
+			ID:       saved.ID,
+			JobTitle: saved.JobTitle,
+			Company:  saved.Company,
+		})
+	}
+	return result, nil
+}
+
+// jsonEqual reports whether two JSON documents hold the same value, ignoring key order and spacing.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
 )
 
+var errMissingAIKey = errors.New("missing Gemini API key (set GEMINI_API_KEY on the server or send X-Gemini-Api-Key)")
+
 func geminiAPIKeyOptional(r *http.Request) string {
 	if r == nil {
 		return strings.TrimSpace(os.Getenv("GEMINI_API_KEY"))
@@ -20,7 +22,7 @@ func geminiAPIKeyOptional(r *http.Request) string {
 func geminiAPIKeyRequired(r *http.Request) (string, error) {
 	key := geminiAPIKeyOptional(r)
 	if key == "" {
-		return "", fmt.Errorf("missing Gemini API key (set GEMINI_API_KEY on the server or send X-Gemini-Api-Key)")
+		return "", errMissingAIKey
 	}
 	return key, nil
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// accountDeletionToken is the response of POST /api/account/deletion-token.
+type accountDeletionToken struct {
+	Token     string    `json:"token"`
+	Header    string    `json:"header"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// handleAccountDeletionToken handles POST /api/account/deletion-token, which issues a short-lived
+// token that DELETE /api/account must send back. Requesting a new token replaces the previous one.
+func handleAccountDeletionToken(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	token, hash, err := newAccountDeletionToken()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	expiresAt := time.Now().Add(accountDeletionTokenTTL).UTC().Truncate(time.Second)
+	if err := s.SetAccountDeletionToken(r.Context(), userID, hash, expiresAt); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(accountDeletionToken{
+		Token:     token,
+		Header:    accountDeletionTokenHeader,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// handleDeleteAccount handles DELETE /api/account: permanently removes the user's profile, applications
+// and everything attached to them, and returns an AccountDeletionReceipt. The request must carry
+// a token from POST /api/account/deletion-token in the X-Confirm-Deletion header.
+func handleDeleteAccount(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	token := strings.TrimSpace(r.Header.Get(accountDeletionTokenHeader))
+	if token == "" {
+		writeError(w, r, newAPIError(http.StatusPreconditionRequired, codePreconditionRequired,
+			accountDeletionTokenHeader+" header is required; request a token from POST /api/account/deletion-token"))
+		return
+	}
+	if !strings.HasPrefix(token, accountDeletionTokenPrefix) {
+		writeError(w, r, errInvalidDeletionToken)
+		return
+	}
+
+	receipt, blobKeys, err := s.DeleteAccount(r.Context(), userID, hashAccountDeletionToken(token))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	receipt.ReceiptID = uuid.New().String()
+
+	// The rows are gone; the blobs are removed best-effort and don't fail the request.
+	for _, key := range blobKeys {
+		if err := blobs.Delete(r.Context(), key); err != nil {
+			loggerFrom(r.Context()).Warn("account deletion: delete blob failed", "receiptId", receipt.ReceiptID, "blob", key, "err", err)
+			receipt.BlobsFailed++
+			continue
+		}
+		receipt.BlobsDeleted++
+	}
+	loggerFrom(r.Context()).Info("account deleted", "receiptId", receipt.ReceiptID,
+		"applications", receipt.Applications, "blobsDeleted", receipt.BlobsDeleted, "blobsFailed", receipt.BlobsFailed)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(receipt)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleAnalytics handles GET /api/analytics[?from=YYYY-MM-DD][&to=YYYY-MM-DD][&tag=a,b]: funnel
+// counts, stage conversion rates, median time in stage and weekly volume for the user's pipeline.
+func handleAnalytics(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	q, err := parseAnalyticsQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	analytics, err := s.Analytics(r.Context(), userID, q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
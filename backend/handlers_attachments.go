@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// multipartOverhead is headroom for multipart boundaries and headers on top of the file itself.
+const multipartOverhead = 1 << 20
+
+// handleListAttachments handles GET /api/applications/{id}/attachments.
+func handleListAttachments(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	files, err := s.ListAttachments(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(files)
+}
+
+// handleDeleteAttachment handles DELETE /api/applications/{id}/attachments/{attachmentId}.
+func handleDeleteAttachment(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	deleted, err := s.DeleteAttachment(r.Context(), userID, r.PathValue("id"), r.PathValue("attachmentId"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Attachment not found"))
+		return
+	}
+	// The record is gone, so the attachment is deleted as far as the user is concerned even
+	// if removing the blob fails.
+	deleteBlobs(r.Context(), deleted.BlobKey)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUploadAttachment handles POST /api/applications/{id}/attachments, storing the first
+// "file" part of a multipart/form-data upload. The content type is
+// sniffed from the bytes; the bytes go to the blob store first and the record is then written
+// under the user's quota, deleting the blob again if the record can't be saved.
+func handleUploadAttachment(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	appID := r.PathValue("id")
+	if _, err := s.GetApplication(r.Context(), userID, appID); err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, attachLimits.MaxFileBytes+multipartOverhead)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, r, badRequest("Expected a multipart/form-data upload with a \"file\" field"))
+		return
+	}
+
+	var (
+		filename string
+		data     []byte
+	)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeUploadReadError(w, r, err)
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+		filename = sanitizeAttachmentFilename(part.FileName())
+		data, err = io.ReadAll(io.LimitReader(part, attachLimits.MaxFileBytes+1))
+		part.Close()
+		if err != nil {
+			writeUploadReadError(w, r, err)
+			return
+		}
+		break
+	}
+	if filename == "" {
+		writeError(w, r, badRequest("Missing \"file\" field"))
+		return
+	}
+	if len(data) == 0 {
+		writeError(w, r, badRequest("Uploaded file is empty"))
+		return
+	}
+	if int64(len(data)) > attachLimits.MaxFileBytes {
+		writeError(w, r, fileTooLarge())
+		return
+	}
+	contentType, err := detectAttachmentType(data, filename)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Fail fast before touching the blob store; CreateAttachment re-checks atomically.
+	used, err := s.AttachmentUsage(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if used+int64(len(data)) > attachLimits.QuotaBytes {
+		writeError(w, r, quotaExceeded())
+		return
+	}
+
+	att := Attachment{
+		ID:            uuid.NewString(),
+		ApplicationID: appID,
+		Filename:      filename,
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+		SHA256:        sha256Hex(data),
+	}
+	att.BlobKey = attachmentBlobKey(userID, appID, att.ID)
+
+	if err := blobs.Put(r.Context(), att.BlobKey, bytes.NewReader(data), att.Size, contentType); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	saved, err := s.CreateAttachment(r.Context(), userID, att, attachLimits.QuotaBytes)
+	if err != nil {
+		deleteBlobs(r.Context(), att.BlobKey)
+		if errors.Is(err, errAttachmentQuota) {
+			writeError(w, r, quotaExceeded())
+			return
+		}
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// handleDownloadAttachment handles GET /api/applications/{id}/attachments/{attachmentId}.
+func handleDownloadAttachment(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	att, err := s.GetAttachment(r.Context(), userID, r.PathValue("id"), r.PathValue("attachmentId"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Attachment not found"))
+		return
+	}
+	body, err := blobs.Get(r.Context(), att.BlobKey)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Attachment content is missing"))
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", att.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(att.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": att.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", `"`+att.SHA256+`"`)
+	if _, err := io.Copy(w, body); err != nil {
+		loggerFrom(r.Context()).Warn("stream attachment failed", "attachmentId", att.ID, "err", err)
+	}
+}
+
+// handleAttachmentUsage handles GET /api/attachments/usage.
+func handleAttachmentUsage(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	used, err := s.AttachmentUsage(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AttachmentUsage{
+		UsedBytes:    used,
+		QuotaBytes:   attachLimits.QuotaBytes,
+		MaxFileBytes: attachLimits.MaxFileBytes,
+	})
+}
+
+func writeUploadReadError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, r, fileTooLarge())
+		return
+	}
+	writeError(w, r, badRequest("Failed to read upload: "+err.Error()))
+}
+
+func fileTooLarge() *apiError {
+	return newAPIError(http.StatusRequestEntityTooLarge, codePayloadTooLarge, "File is larger than "+formatMB(attachLimits.MaxFileBytes))
+}
+
+func quotaExceeded() *apiError {
+	return newAPIError(http.StatusRequestEntityTooLarge, codeQuotaExceeded, "Attachment quota of "+formatMB(attachLimits.QuotaBytes)+" exceeded")
+}
+
+func formatMB(n int64) string {
+	return strconv.FormatInt(n>>20, 10) + " MB"
+}
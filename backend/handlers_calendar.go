@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// handleCalendarExport handles GET /api/calendar.ics: a one-off download of the user's calendar.
+func handleCalendarExport(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	writeCalendar(w, r, s, userID, `attachment; filename="applications.ics"`)
+}
+
+// calendarFeed is the response of POST /api/calendar/feed.
+type calendarFeed struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// handleCreateCalendarFeed handles POST /api/calendar/feed, which issues (or rotates) the
+// user's feed token and returns the subscription URL. The token is only ever shown here.
+func handleCreateCalendarFeed(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	token, hash, err := newCalendarToken()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	createdAt, err := s.SetCalendarToken(r.Context(), userID, hash)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendarFeed{
+		URL:       publicBaseURL(r) + "/calendar/" + token + ".ics",
+		Token:     token,
+		CreatedAt: createdAt,
+	})
+}
+
+// handleRevokeCalendarFeed handles DELETE /api/calendar/feed.
+func handleRevokeCalendarFeed(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	if err := s.RevokeCalendarToken(r.Context(), userID); err != nil {
+		writeError(w, r, orNotFound(err, "No calendar feed to revoke"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCalendarFeed handles GET /calendar/{token}.ics. It is deliberately outside requireAuth:
+// calendar clients authenticate with the feed token in the URL instead of a bearer token.
+func handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(r.PathValue("token"), ".ics")
+	if !strings.HasPrefix(token, calendarTokenPrefix) {
+		writeError(w, r, notFound("Calendar not found"))
+		return
+	}
+	s := currentStore()
+	if s == nil {
+		writeError(w, r, errStoreUnavailable)
+		return
+	}
+
+	userID, err := s.UserIDForCalendarToken(r.Context(), hashCalendarToken(token))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Calendar not found"))
+		return
+	}
+	r = r.WithContext(withUser(r.Context(), userID))
+	writeCalendar(w, r, s, userID, "")
+}
+
+func writeCalendar(w http.ResponseWriter, r *http.Request, s Store, userID, disposition string) {
+	schedules, err := s.ListOpenSchedules(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	body := buildICS("Job applications", calendarEvents(schedules), time.Now())
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if disposition != "" {
+		w.Header().Set("Content-Disposition", disposition)
+	}
+	w.Write(body)
+}
+
+// publicBaseURL is the externally reachable origin of the backend: PUBLIC_BASE_URL if set,
+// otherwise derived from the request (honouring X-Forwarded-Proto behind a proxy).
+func publicBaseURL(r *http.Request) string {
+	if base := strings.TrimRight(strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL")), "/"); base != "" {
+		return base
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")); proto != "" {
+		scheme = strings.ToLower(strings.TrimSpace(strings.Split(proto, ",")[0]))
+	}
+	return scheme + "://" + r.Host
+}
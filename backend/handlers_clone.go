@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// cloneRequest optionally overrides fields of the cloned application. Omitted fields are copied.
+type cloneRequest struct {
+	JobTitle       *string `json:"jobTitle"`
+	Company        *string `json:"company"`
+	JobDescription *string `json:"jobDescription"`
+}
+
+// handleApplicationClone handles POST /api/applications/{id}/clone. The copy keeps the resume,
+// cover letter and tags, starts over as "saved" without dates, interviews or contacts, and
+// records the source as its parent.
+func handleApplicationClone(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var req cloneRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSONBody(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	var errs fieldErrors
+	if req.JobTitle != nil {
+		errs.text("jobTitle", *req.JobTitle, maxShortTextLength, true)
+	}
+	if req.Company != nil {
+		errs.text("company", *req.Company, maxShortTextLength, true)
+	}
+	if req.JobDescription != nil {
+		errs.text("jobDescription", *req.JobDescription, maxJobDescriptionLength, false)
+	}
+	if err := errs.err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	src, err := s.GetApplication(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+
+	clone := Application{
+		JobTitle:          src.JobTitle,
+		Company:           src.Company,
+		ApplicationStatus: statusSaved,
+		JobDescription:    src.JobDescription,
+		Resume:            src.Resume,
+		CoverLetter:       src.CoverLetter,
+		Tags:              src.Tags,
+		ParentID:          src.ID,
+	}
+	if req.JobTitle != nil {
+		clone.JobTitle = strings.TrimSpace(*req.JobTitle)
+	}
+	if req.Company != nil {
+		clone.Company = strings.TrimSpace(*req.Company)
+	}
+	if req.JobDescription != nil {
+		clone.JobDescription = *req.JobDescription
+	}
+
+	created, err := s.CreateApplication(r.Context(), userID, clone)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(created.Version))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// handleListContacts handles GET /api/contacts.
+func handleListContacts(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	contacts, err := s.ListContacts(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contacts)
+}
+
+// handleCreateContact handles POST /api/contacts.
+func handleCreateContact(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var c Contact
+	if err := decodeJSONBody(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	created, err := s.CreateContact(r.Context(), userID, c)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// handleGetContact handles GET /api/contacts/{id}.
+func handleGetContact(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	c, err := s.GetContact(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Contact not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// handleUpdateContact handles PUT /api/contacts/{id}.
+func handleUpdateContact(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	id := r.PathValue("id")
+	var c Contact
+	if err := decodeJSONBody(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if c.ID != "" && c.ID != id {
+		writeError(w, r, badRequest("Contact ID in URL and body do not match"))
+		return
+	}
+	c.ID = id
+	saved, err := s.UpdateContact(r.Context(), userID, c)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Contact not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// handleDeleteContact handles DELETE /api/contacts/{id}.
+func handleDeleteContact(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	if err := s.DeleteContact(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeError(w, r, orNotFound(err, "Contact not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListApplicationContacts handles GET /api/applications/{id}/contacts: contacts linked to
+// the application, primary first.
+func handleListApplicationContacts(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	contacts, err := s.ListApplicationContacts(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contacts)
+}
+
+// linkContactRequest is the optional body of PUT /api/applications/{id}/contacts/{contactId}.
+type linkContactRequest struct {
+	Primary *bool `json:"primary"`
+}
+
+// handleLinkContact handles PUT /api/applications/{id}/contacts/{contactId}. The body
+// {"primary": true|false} is optional.
+func handleLinkContact(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var body linkContactRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSONBody(w, r, &body); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	linked, err := s.LinkContact(r.Context(), userID, r.PathValue("id"), r.PathValue("contactId"), body.Primary)
+	if err != nil {
+		switch {
+		case errors.Is(err, errLinkContactNotFound):
+			writeError(w, r, notFound("Contact not found"))
+		default:
+			writeError(w, r, orNotFound(err, "Application not found"))
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(linked)
+}
+
+// handleUnlinkContact handles DELETE /api/applications/{id}/contacts/{contactId}.
+func handleUnlinkContact(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	if err := s.UnlinkContact(r.Context(), userID, r.PathValue("id"), r.PathValue("contactId")); err != nil {
+		writeError(w, r, orNotFound(err, "Contact is not linked to this application"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// primaryContactFor looks up the primary contact of a saved application so the cover letter can
+// fall back to it. It returns nil for unsaved applications or when there is no primary contact.
+func primaryContactFor(r *http.Request, app Application) *Contact {
+	if strings.TrimSpace(app.ID) == "" {
+		return nil
+	}
+	userID, err := userIDFromRequest(r)
+	if err != nil {
+		return nil
+	}
+	s := currentStore()
+	if s == nil {
+		return nil
+	}
+	c, err := s.PrimaryContact(r.Context(), userID, app.ID)
+	if err != nil {
+		if !errors.Is(err, errNotFound) {
+			loggerFrom(r.Context()).Warn("primary contact lookup failed", "applicationId", app.ID, "err", err)
+		}
+		return nil
+	}
+	return &c
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// handleExport handles GET /api/export[?pdfs=1]: a ZIP of the profile and every live
+// application, optionally with their resume and cover letter PDFs rendered through pdflatex.
+func handleExport(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var render exportPDFRenderer
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("pdfs"))) {
+	case "", "0", "false":
+	case "1", "true":
+		latexPath, err := exec.LookPath("pdflatex")
+		if err != nil {
+			writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeRendererUnavailable, "pdflatex not found in PATH; export without pdfs=1 or install TeX Live"))
+			return
+		}
+		render = func(app Application) ([]byte, []byte, error) {
+			return generateResumeAndCoverPDFs(r.Context(), latexPath, app, primaryContactFor(r, app))
+		}
+	default:
+		writeError(w, r, badRequest("invalid pdfs (use pdfs=1 or pdfs=0)"))
+		return
+	}
+
+	// Build the archive before responding so a failure can still return an error status.
+	var buf bytes.Buffer
+	if err := writeAccountExport(r.Context(), &buf, s, userID, render); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filename := fmt.Sprintf("jobapp-export-%s.zip", time.Now().UTC().Format(dateLayout))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(buf.Bytes())
+}
+
+// handleImport handles POST /api/import. The body (or the multipart "file" field) is an archive
+// from GET /api/export. Importing is idempotent: applications that already exist are skipped.
+func handleImport(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxExportUploadSize)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeArchiveReadError(w, r, err, "missing file field: ")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		writeArchiveReadError(w, r, err, "failed to read upload: ")
+		return
+	}
+
+	archive, err := readAccountArchive(data)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	result, err := importAccountArchive(r.Context(), s, userID, archive)
+	if err != nil {
+		if errors.Is(err, errVersionConflict) {
+			writeError(w, r, newAPIError(http.StatusConflict, codeConflict, "The profile changed during the import; retry it"))
+			return
+		}
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func writeArchiveReadError(w http.ResponseWriter, r *http.Request, err error, prefix string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, r, newAPIError(http.StatusRequestEntityTooLarge, codePayloadTooLarge, "Archive is larger than "+formatMB(tooLarge.Limit)))
+		return
+	}
+	writeError(w, r, badRequest(prefix+err.Error()))
+}
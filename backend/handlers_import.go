@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const maxLegacyUploadSize = 32 << 20
+
+// handleLegacyImport handles POST /api/import/legacy. The body (or the multipart "file" field)
+// is either a single legacy JSON file or a zip archive of them.
+func handleLegacyImport(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLegacyUploadSize)
+	name := "upload.json"
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeArchiveReadError(w, r, err, "missing file field: ")
+			return
+		}
+		defer file.Close()
+		name = header.Filename
+		body = file
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		writeArchiveReadError(w, r, err, "failed to read upload: ")
+		return
+	}
+
+	var files []legacyFile
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		files, err = readLegacyArchive(data)
+		if err != nil {
+			writeError(w, r, badRequest(err.Error()))
+			return
+		}
+	} else {
+		files = []legacyFile{{Name: name, Data: data}}
+	}
+
+	result, err := importLegacyFiles(r.Context(), s, userID, files)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// handleGetOffer handles GET /api/applications/{id}/offer.
+func handleGetOffer(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	offer, err := s.GetOffer(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Offer not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offer)
+}
+
+// handlePutOffer handles PUT /api/applications/{id}/offer, creating or replacing the offer. The
+// negotiation history is kept.
+func handlePutOffer(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var o Offer
+	if err := decodeJSONBody(w, r, &o); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	saved, created, err := s.PutOffer(r.Context(), userID, r.PathValue("id"), o)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(saved)
+}
+
+// handleDeleteOffer handles DELETE /api/applications/{id}/offer.
+func handleDeleteOffer(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	if err := s.DeleteOffer(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeError(w, r, orNotFound(err, "Offer not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAppendNegotiation handles POST /api/applications/{id}/offer/negotiation, appending a
+// negotiation event {"by", "amount", "note", "at"}.
+func handleAppendNegotiation(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var e NegotiationEvent
+	if err := decodeJSONBody(w, r, &e); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	offer, err := s.AppendNegotiation(r.Context(), userID, r.PathValue("id"), e)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Offer not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(offer)
+}
+
+// handleOffers handles GET /api/offers: every offer on a live application.
+func handleOffers(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	offers, err := s.ListOffers(r.Context(), userID, nil)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offers)
+}
+
+// handleOfferCompare handles GET /api/offers/compare?ids=a,b[&currency=USD][&rates=EUR:1.08,...].
+// Offers are annualized into currency (the first offer's currency by default) and ranked.
+// rates add to or override the server's OFFER_FX_RATES.
+func handleOfferCompare(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	query := r.URL.Query()
+	var ids []string
+	for _, raw := range query["ids"] {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			// Offer ids are compared in their canonical form, so any spelling of a UUID matches.
+			parsed, err := uuid.Parse(part)
+			if err != nil {
+				writeError(w, r, badRequest("ids must be offer ids (UUIDs): "+part))
+				return
+			}
+			if id := parsed.String(); !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		writeError(w, r, badRequest("ids is required (comma-separated offer ids)"))
+		return
+	}
+	currency := strings.ToUpper(strings.TrimSpace(query.Get("currency")))
+	if currency != "" && !isCurrencyCode(currency) {
+		writeError(w, r, badRequest("currency must be a 3-letter ISO 4217 code"))
+		return
+	}
+	rates := maps.Clone(offerFXRates)
+	if rates == nil {
+		rates = fxRates{}
+	}
+	if err := parseFXRates(query.Get("rates"), rates); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	offers, err := s.ListOffers(r.Context(), userID, ids)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	byID := map[string]Offer{}
+	for _, o := range offers {
+		byID[o.ID] = o
+	}
+	ordered := make([]Offer, 0, len(ids))
+	for _, id := range ids {
+		o, ok := byID[id]
+		if !ok {
+			writeError(w, r, notFound("Offer not found: "+id))
+			return
+		}
+		ordered = append(ordered, o)
+	}
+
+	result, err := compareOffers(ordered, currency, rates)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -4,60 +4,72 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-
-	"github.com/jackc/pgx/v5"
 )
 
-func handleProfile(w http.ResponseWriter, r *http.Request) {
-	userID, err := userIDFromRequest(r)
+// handleGetProfile handles GET /api/profile.
+func handleGetProfile(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	raw, version, err := s.GetProfile(r.Context(), userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		writeError(w, r, orNotFound(err, "profile not found"))
 		return
 	}
-	storeMu.RLock()
-	s := store
-	storeMu.RUnlock()
-	if s == nil {
-		http.Error(w, "database not ready", http.StatusServiceUnavailable)
-		return
+	if len(raw) == 0 {
+		raw = json.RawMessage(`{}`)
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(version))
+	w.Write(raw)
+}
 
-	switch r.Method {
-	case http.MethodGet:
-		raw, err := s.GetProfile(r.Context(), userID)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				http.Error(w, "profile not found", http.StatusNotFound)
-				return
-			}
-			http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+// handlePutProfile handles PUT /api/profile. It requires If-Match, or If-None-Match: * to create
+// the first profile.
+func handlePutProfile(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	ifMatch, err := ifMatchVersion(r, true)
+	if err != nil {
+		if errors.Is(err, errPreconditionRequired) {
+			writeError(w, r, newAPIError(http.StatusPreconditionRequired, codePreconditionRequired, "If-Match (or If-None-Match: * to create) header is required"))
 			return
 		}
-		if len(raw) == 0 {
-			raw = json.RawMessage(`{}`)
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(raw)
+		writeError(w, r, err)
+		return
+	}
 
-	case http.MethodPut:
-		var raw json.RawMessage
-		dec := json.NewDecoder(r.Body)
-		if err := dec.Decode(&raw); err != nil {
-			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+	var raw json.RawMessage
+	if err := decodeJSONBody(w, r, &raw); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(raw) == 0 {
+		raw = json.RawMessage(`{}`)
+	}
+
+	version, err := s.UpsertProfile(r.Context(), userID, raw, ifMatch)
+	if err != nil {
+		if errors.Is(err, errVersionConflict) {
+			writeProfileConflict(w, r, s, userID)
 			return
 		}
-		if len(raw) == 0 {
-			raw = json.RawMessage(`{}`)
-		}
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(version))
+	w.Write(raw)
+}
 
-		if err := s.UpsertProfile(r.Context(), userID, raw); err != nil {
-			http.Error(w, "Failed to save profile: "+err.Error(), http.StatusInternalServerError)
+// writeProfileConflict replies 412 with the server's current profile in details.current so the
+// client can reconcile.
+func writeProfileConflict(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	conflict := newAPIError(http.StatusPreconditionFailed, codeVersionConflict, errVersionConflict.Error())
+	current, version, err := s.GetProfile(r.Context(), userID)
+	if err != nil {
+		if isNotFound(err) {
+			writeError(w, r, conflict.withDetails(map[string]any{"current": nil}))
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(raw)
-
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, r, err)
+		return
 	}
+	w.Header().Set("ETag", formatETag(version))
+	writeError(w, r, conflict.withDetails(map[string]any{"current": current}))
 }
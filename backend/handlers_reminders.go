@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxReminderLookaheadDays = 90
+
+// handleReminders handles GET /api/reminders: overdue next actions (follow-ups, deadlines,
+// interviews) across the user's open applications, soonest first. ?days=N also includes
+// actions due within the next N days.
+func handleReminders(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	days := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("days")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > maxReminderLookaheadDays {
+			writeError(w, r, badRequest("days must be between 0 and "+strconv.Itoa(maxReminderLookaheadDays)))
+			return
+		}
+		days = n
+	}
+
+	schedules, err := s.ListOpenSchedules(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	reminders := buildReminders(schedules, time.Now(), time.Duration(days)*24*time.Hour)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reminders)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// handleListRenders handles GET /api/applications/{id}/renders: saved renders, newest first.
+func handleListRenders(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	renders, err := s.ListRenders(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(renders)
+}
+
+// handleGetRender handles GET /api/applications/{id}/renders/{renderId}: one render with its
+// input snapshot.
+func handleGetRender(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	render, err := s.GetRender(r.Context(), userID, r.PathValue("id"), r.PathValue("renderId"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Render not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(render)
+}
+
+// handleDownloadRender handles GET /api/applications/{id}/renders/{renderId}/download: the ZIP as
+// originally downloaded, or with ?doc=resume|cover just one PDF.
+func handleDownloadRender(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	render, err := s.GetRender(r.Context(), userID, r.PathValue("id"), r.PathValue("renderId"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Render not found"))
+		return
+	}
+
+	var key, filename, sum string
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("doc"))) {
+	case "":
+		resumePDF, err := readBlob(r.Context(), render.ResumeBlobKey)
+		if err != nil {
+			writeError(w, r, orNotFound(err, "Stored PDF is missing"))
+			return
+		}
+		coverPDF, err := readBlob(r.Context(), render.CoverBlobKey)
+		if err != nil {
+			writeError(w, r, orNotFound(err, "Stored PDF is missing"))
+			return
+		}
+		zipBytes, err := zipDocuments(resumePDF, coverPDF, render.ResumeFilename, render.CoverFilename)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		var input renderInput
+		if err := json.Unmarshal(render.Input, &input); err != nil {
+			loggerFrom(r.Context()).Warn("decode render input snapshot failed", "renderId", render.ID, "err", err)
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pdfZipFilename(input.Application)))
+		w.Write(zipBytes)
+		return
+	case "resume":
+		key, filename, sum = render.ResumeBlobKey, render.ResumeFilename, render.ResumeSHA256
+	case "cover", "cover_letter", "coverletter":
+		key, filename, sum = render.CoverBlobKey, render.CoverFilename, render.CoverSHA256
+	default:
+		writeError(w, r, badRequest("invalid doc (use doc=resume or doc=cover)"))
+		return
+	}
+
+	pdf, err := readBlob(r.Context(), key)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Stored PDF is missing"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("ETag", `"`+sum+`"`)
+	w.Write(pdf)
+}
+
+// saveRender stores generated PDFs and the input they came from against a saved application.
+// The PDFs are written to the blob store first and removed again if the record can't be saved.
+func saveRender(ctx context.Context, s Store, userID string, stored Application, input renderInput, resumePDF, coverPDF []byte, resumeFilename, coverFilename string) (PDFRender, error) {
+	snapshot, err := json.Marshal(input)
+	if err != nil {
+		return PDFRender{}, err
+	}
+	render := PDFRender{
+		ID:                 uuid.NewString(),
+		ApplicationID:      stored.ID,
+		ApplicationVersion: stored.Version,
+		ResumeFilename:     resumeFilename,
+		ResumeSHA256:       sha256Hex(resumePDF),
+		ResumeSize:         int64(len(resumePDF)),
+		CoverFilename:      coverFilename,
+		CoverSHA256:        sha256Hex(coverPDF),
+		CoverSize:          int64(len(coverPDF)),
+		Input:              snapshot,
+	}
+	render.ResumeBlobKey = renderBlobKey(userID, stored.ID, render.ID, "resume")
+	render.CoverBlobKey = renderBlobKey(userID, stored.ID, render.ID, "cover_letter")
+
+	if err := blobs.Put(ctx, render.ResumeBlobKey, bytes.NewReader(resumePDF), render.ResumeSize, "application/pdf"); err != nil {
+		return PDFRender{}, err
+	}
+	if err := blobs.Put(ctx, render.CoverBlobKey, bytes.NewReader(coverPDF), render.CoverSize, "application/pdf"); err != nil {
+		deleteBlobs(ctx, render.ResumeBlobKey)
+		return PDFRender{}, err
+	}
+	saved, err := s.CreateRender(ctx, userID, render)
+	if err != nil {
+		deleteBlobs(ctx, render.ResumeBlobKey, render.CoverBlobKey)
+		return PDFRender{}, err
+	}
+	return saved, nil
+}
+
+func readBlob(ctx context.Context, key string) ([]byte, error) {
+	body, err := blobs.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := blobs.Delete(ctx, key); err != nil {
+			loggerFrom(ctx).Warn("delete blob failed", "blob", key, "err", err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// handleListRevisions handles GET /api/applications/{id}/revisions, newest first.
+func handleListRevisions(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	revisions, err := s.ListRevisions(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// handleGetRevision handles GET /api/applications/{id}/revisions/{rev}: one revision with content.
+func handleGetRevision(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	revID, err := strconv.ParseInt(r.PathValue("rev"), 10, 64)
+	if err != nil {
+		writeError(w, r, badRequest("invalid revision id"))
+		return
+	}
+	rev, err := s.GetRevision(r.Context(), userID, r.PathValue("id"), revID)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Revision not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rev)
+}
+
+// handleRestoreRevision handles POST /api/applications/{id}/revisions/{rev}/restore, restoring a
+// revision onto the application. Like an update it requires If-Match, and a stale version gets
+// a version_conflict error carrying the current copy.
+func handleRestoreRevision(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	id := r.PathValue("id")
+	ifMatch, err := ifMatchVersion(r, false)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	revID, err := strconv.ParseInt(r.PathValue("rev"), 10, 64)
+	if err != nil {
+		writeError(w, r, badRequest("invalid revision id"))
+		return
+	}
+	app, err := s.RestoreRevision(r.Context(), userID, id, revID, ifMatch)
+	if err != nil {
+		if errors.Is(err, errVersionConflict) {
+			writeApplicationConflict(w, r, s, userID, id)
+			return
+		}
+		writeError(w, r, orNotFound(err, "Revision not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(app.Version))
+	json.NewEncoder(w).Encode(app)
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleApplicationHistory handles GET /api/applications/{id}/history.
+func handleApplicationHistory(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	history, err := s.ListStatusHistory(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
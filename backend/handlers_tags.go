@@ -0,0 +1,17 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleTags handles GET /api/tags: the user's tags in use, with how many applications carry each.
+func handleTags(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	tags, err := s.ListTagCounts(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleApplicationRestore handles POST /api/applications/{id}/restore, taking an application
+// back out of the trash.
+func handleApplicationRestore(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	app, err := s.RestoreApplication(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found in trash"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(app.Version))
+	json.NewEncoder(w).Encode(app)
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Legacy data predates the database: applications were stored as backend/applications/*.json
+// and the base resume as backend/data/resume_data.json using kebab-case keys.
+
+const maxLegacyFileSize = 5 << 20
+
+var errNotLegacyData = errors.New("not a recognized application or resume file")
+
+// legacyResumeData mirrors the old kebab-case resume_data.json schema.
+type legacyResumeData struct {
+	Name            string                `json:"name"`
+	Phone           string                `json:"number"`
+	Email           string                `json:"email"`
+	LinkedIn        string                `json:"linkedin"`
+	Github          string                `json:"github"`
+	Objective       string                `json:"objective"`
+	RelevantCourses StringList            `json:"relavent-courses"`
+	Jobs            []legacyJob           `json:"jobs"`
+	Projects        []legacyProject       `json:"projects"`
+	SkillCategories []legacySkillCategory `json:"skill_categories"`
+}
+
+type legacyJob struct {
+	JobTitle     string     `json:"job-title"`
+	JobStartDate string     `json:"job-start-date"`
+	JobEndDate   string     `json:"job-end-date"`
+	JobEmployer  string     `json:"job-employer"`
+	JobLocation  string     `json:"job-location"`
+	JobPoints    StringList `json:"job-points"`
+}
+
+type legacyProject struct {
+	ProjectTitle  string     `json:"project-title"`
+	ProjectTech   string     `json:"project-tech"`
+	ProjectDate   string     `json:"project-date"`
+	ProjectPoints StringList `json:"project-points"`
+}
+
+type legacySkillCategory struct {
+	CatTitle  string     `json:"cat-title"`
+	CatSkills StringList `json:"cat-skills"`
+}
+
+func (l legacyResumeData) toResumeData() ResumeData {
+	res := ResumeData{
+		Name:            l.Name,
+		Phone:           l.Phone,
+		Email:           l.Email,
+		LinkedIn:        l.LinkedIn,
+		Github:          l.Github,
+		Objective:       l.Objective,
+		RelevantCourses: l.RelevantCourses,
+	}
+	for _, j := range l.Jobs {
+		res.Jobs = append(res.Jobs, Job{
+			JobTitle:     j.JobTitle,
+			JobStartDate: j.JobStartDate,
+			JobEndDate:   j.JobEndDate,
+			JobEmployer:  j.JobEmployer,
+			JobLocation:  j.JobLocation,
+			JobPoints:    j.JobPoints,
+		})
+	}
+	for _, p := range l.Projects {
+		res.Projects = append(res.Projects, Project{
+			ProjectTitle:  p.ProjectTitle,
+			ProjectTech:   p.ProjectTech,
+			ProjectDate:   p.ProjectDate,
+			ProjectPoints: p.ProjectPoints,
+		})
+	}
+	for _, c := range l.SkillCategories {
+		res.SkillCategories = append(res.SkillCategories, SkillCategory{
+			CatTitle:  c.CatTitle,
+			CatSkills: c.CatSkills,
+		})
+	}
+	return normalizeOptimizedResume(res, ResumeData{})
+}
+
+// isLegacyResume reports whether a resume object uses the old kebab-case keys.
+func isLegacyResume(fields map[string]json.RawMessage) bool {
+	for _, k := range []string{"relavent-courses", "skill_categories"} {
+		if _, ok := fields[k]; ok {
+			return true
+		}
+	}
+	if raw, ok := fields["jobs"]; ok && bytes.Contains(raw, []byte(`"job-title"`)) {
+		return true
+	}
+	if raw, ok := fields["projects"]; ok && bytes.Contains(raw, []byte(`"project-title"`)) {
+		return true
+	}
+	return false
+}
+
+// decodeResume decodes a resume in either the current or the legacy schema.
+func decodeResume(raw json.RawMessage) (ResumeData, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return ResumeData{}, err
+	}
+	if isLegacyResume(fields) {
+		var legacy legacyResumeData
+		if err := json.Unmarshal(raw, &legacy); err != nil {
+			return ResumeData{}, err
+		}
+		return legacy.toResumeData(), nil
+	}
+	var res ResumeData
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return ResumeData{}, err
+	}
+	return res, nil
+}
+
+// parseLegacyFile maps one legacy JSON file onto an Application. Application files keep their
+// id/title/company/status; a bare resume file becomes a "saved" application titled after the file.
+func parseLegacyFile(name string, data []byte) (Application, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Application{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if rawResume, ok := fields["resume"]; ok {
+		var legacy struct {
+			ID                string          `json:"id"`
+			JobTitle          string          `json:"jobTitle"`
+			Company           string          `json:"company"`
+			ApplicationStatus string          `json:"applicationStatus"`
+			JobDescription    string          `json:"jobDescription"`
+			CoverLetter       *CoverLetter    `json:"coverLetter"`
+			Resume            json.RawMessage `json:"resume"`
+		}
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return Application{}, err
+		}
+		resume, err := decodeResume(rawResume)
+		if err != nil {
+			return Application{}, fmt.Errorf("invalid resume: %w", err)
+		}
+		status, err := normalizeApplicationStatus(legacy.ApplicationStatus)
+		if err != nil {
+			status = statusApplied
+		}
+		return Application{
+			ID:                strings.TrimSpace(legacy.ID),
+			JobTitle:          legacy.JobTitle,
+			Company:           legacy.Company,
+			ApplicationStatus: status,
+			JobDescription:    legacy.JobDescription,
+			Resume:            resume,
+			CoverLetter:       legacy.CoverLetter,
+		}, nil
+	}
+
+	_, hasJobs := fields["jobs"]
+	_, hasObjective := fields["objective"]
+	if !hasJobs && !hasObjective {
+		return Application{}, errNotLegacyData
+	}
+	resume, err := decodeResume(data)
+	if err != nil {
+		return Application{}, fmt.Errorf("invalid resume: %w", err)
+	}
+	title := strings.TrimSuffix(path.Base(filepath.ToSlash(name)), path.Ext(name))
+	return Application{
+		JobTitle:          "Imported resume (" + title + ")",
+		ApplicationStatus: statusSaved,
+		Resume:            resume,
+	}, nil
+}
+
+// legacyFile is one JSON document found on disk or inside an uploaded archive.
+type legacyFile struct {
+	Name string
+	Data []byte
+}
+
+// readLegacyPaths collects *.json files from the given files and directories (non-recursive).
+func readLegacyPaths(paths []string) ([]legacyFile, error) {
+	var out []legacyFile
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		files := []string{p}
+		if info.IsDir() {
+			files, err = filepath.Glob(filepath.Join(p, "*.json"))
+			if err != nil {
+				return nil, err
+			}
+			sort.Strings(files)
+		}
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, legacyFile{Name: f, Data: data})
+		}
+	}
+	return out, nil
+}
+
+// readLegacyArchive extracts every *.json entry from a zip archive.
+func readLegacyArchive(data []byte) ([]legacyFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	var out []legacyFile
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(f.Name), ".json") {
+			continue
+		}
+		// Skip macOS resource forks.
+		if strings.HasPrefix(path.Base(f.Name), "._") || strings.Contains(f.Name, "__MACOSX/") {
+			continue
+		}
+		if f.UncompressedSize64 > maxLegacyFileSize {
+			return nil, fmt.Errorf("%s exceeds %d bytes", f.Name, maxLegacyFileSize)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(io.LimitReader(rc, maxLegacyFileSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, legacyFile{Name: f.Name, Data: body})
+	}
+	return out, nil
+}
+
+// ImportResult reports what happened to each file of a legacy import.
+type ImportResult struct {
+	Imported []ImportedItem `json:"imported"`
+	Skipped  []SkippedItem  `json:"skipped"`
+}
+
+type ImportedItem struct {
+	Source   string `json:"source"`
+	ID       string `json:"id"`
+	JobTitle string `json:"jobTitle"`
+	Company  string `json:"company"`
+}
+
+type SkippedItem struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// importLegacyFiles maps each file onto an Application and stores it for userID.
+// Files that were already imported (same application id) are skipped.
+func importLegacyFiles(ctx context.Context, s Store, userID string, files []legacyFile) (ImportResult, error) {
+	result := ImportResult{Imported: []ImportedItem{}, Skipped: []SkippedItem{}}
+	for _, f := range files {
+		app, err := parseLegacyFile(f.Name, f.Data)
+		if err != nil {
+			result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: err.Error()})
+			continue
+		}
+		if _, err := uuid.Parse(app.ID); err != nil {
+			// Derive a stable id from the content so re-importing the same file is a no-op.
+			app.ID = uuid.NewSHA1(uuid.NameSpaceOID, append([]byte(userID+"\x00"), f.Data...)).String()
+		}
+		saved, created, err := s.ImportApplication(ctx, userID, app)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if !created {
+			result.Skipped = append(result.Skipped, SkippedItem{Source: f.Name, Reason: "already imported as " + saved.ID})
+			continue
+		}
+		result.Imported = append(result.Imported, ImportedItem{
+			Source:   f.Name,
+			ID:       saved.ID,
+			JobTitle: saved.JobTitle,
+			Company:  saved.Company,
+		})
+	}
+	return result, nil
+}
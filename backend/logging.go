@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ctxLogger  ctxKey = "logger"
+	ctxRequest ctxKey = "requestLog"
+)
+
+// newLogger returns the process logger: JSON lines on w at LOG_LEVEL (debug, info, warn or
+// error; default info), with credentials redacted from every attribute.
+func newLogger(w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(os.Getenv("LOG_LEVEL")))); err != nil {
+		level = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: redactLogAttr}))
+}
+
+// fatal logs err and exits; the slog counterpart of log.Fatal.
+func fatal(err error) {
+	slog.Error(err.Error())
+	os.Exit(1)
+}
+
+// Credentials are dropped by key, and scrubbed from any string that embeds one (error
+// messages from the AI provider or the JWT library, for example).
+var (
+	sensitiveLogKeys = map[string]bool{
+		"authorization":    true,
+		"x-gemini-api-key": true,
+		"apikey":           true,
+		"api_key":          true,
+		"token":            true,
+		"password":         true,
+	}
+	sensitiveLogValues = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`),
+		regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), // JWTs
+		regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),                             // Google API keys
+		regexp.MustCompile(`(?i)([?&](?:key|api_key|apikey|token)=)[^&\s"']+`),
+		regexp.MustCompile(`\b` + calendarTokenPrefix + `[A-Za-z0-9_-]+`), // calendar feed URLs
+	}
+)
+
+const redacted = "[REDACTED]"
+
+func redactLogAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveLogKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactLogString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, redactLogString(err.Error()))
+		}
+	}
+	return a
+}
+
+func redactLogString(s string) string {
+	for _, re := range sensitiveLogValues {
+		if re.NumSubexp() > 0 {
+			s = re.ReplaceAllString(s, "${1}"+redacted)
+		} else {
+			s = re.ReplaceAllString(s, redacted)
+		}
+	}
+	return s
+}
+
+// withLogger returns ctx carrying l; see loggerFrom.
+func withLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxLogger, l)
+}
+
+// loggerFrom returns the logger for ctx: during a request it carries the request id and, once
+// authenticated, the user id. Outside a request it is the default logger.
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxLogger).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// requestLog collects what the access log reports but only inner handlers learn.
+type requestLog struct {
+	userID string
+}
+
+// withUser records the authenticated user on ctx: for userIDFromRequest, for every later log
+// line of the request, and for its access log entry.
+func withUser(ctx context.Context, userID string) context.Context {
+	if rl, ok := ctx.Value(ctxRequest).(*requestLog); ok {
+		rl.userID = userID
+	}
+	ctx = withLogger(ctx, loggerFrom(ctx).With("userId", userID))
+	return context.WithValue(ctx, ctxUserID, userID)
+}
+
+// statusRecorder captures the status and size of a response for the access log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (for flushing and deadlines).
+func (sr *statusRecorder) Unwrap() http.ResponseWriter { return sr.ResponseWriter }
+
+// withRequestLog tags every request with an id (the caller's X-Request-Id when it looks sane)
+// that is echoed in the response header and in error envelopes, gives the request a logger
+// carrying the id, and writes one access log line per request. Headers and query strings are
+// not logged, so tokens and API keys never reach the access log.
+func withRequestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := strings.TrimSpace(r.Header.Get(requestIDHeader))
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		rl := &requestLog{}
+		logger := slog.Default().With("requestId", id)
+		ctx := context.WithValue(r.Context(), ctxRequestID, id)
+		ctx = context.WithValue(ctx, ctxRequest, rl)
+		ctx = withLogger(ctx, logger)
+		sr := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(sr, r.WithContext(ctx))
+
+		if sr.status == 0 {
+			sr.status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		switch {
+		case sr.status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case sr.status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", sr.status),
+			slog.Float64("durationMs", float64(time.Since(start).Microseconds())/1000),
+			slog.Int64("bytes", sr.bytes),
+		}
+		if rl.userID != "" {
+			attrs = append(attrs, slog.String("userId", rl.userID))
+		}
+		logger.LogAttrs(r.Context(), level, "request", attrs...)
+	})
+}
@@ -5,20 +5,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
-
-	"github.com/jackc/pgx/v5"
 )
 
 var (
-	store    *dbStore
+	store    Store
 	storeMu  sync.RWMutex
 	verifier *jwtVerifier
+	blobs    BlobStore
+	// attachLimits caps attachment uploads; see attachmentLimitsFromEnv.
+	attachLimits attachmentLimits
+	// offerFXRates are the default exchange rates for comparing offers (OFFER_FX_RATES).
+	offerFXRates fxRates
 )
 
 // StringList supports either a JSON array of strings or a comma-separated string.
@@ -57,17 +62,18 @@ func filterAndTrim(items []string) []string {
 
 // ResumeData represents the overall structure of the resume data.
 type ResumeData struct {
-	Name            string          `json:"name"`
-	Phone           string          `json:"number"`
-	Email           string          `json:"email"`
-	LinkedIn        string          `json:"linkedin"`
-	Github          string          `json:"github"`
-	Objective       string          `json:"objective"`
-	RelevantCourses StringList      `json:"relevantCourses"`
-	Jobs            []Job           `json:"jobs"`
-	Projects        []Project       `json:"projects"`
-	SkillCategories []SkillCategory `json:"skillCategories"`
-	Location        string          `json:"location,omitempty"`
+	Name            string           `json:"name"`
+	Phone           string           `json:"number"`
+	Email           string           `json:"email"`
+	LinkedIn        string           `json:"linkedin"`
+	Github          string           `json:"github"`
+	Objective       string           `json:"objective"`
+	RelevantCourses StringList       `json:"relevantCourses"`
+	Education       []EducationEntry `json:"education"`
+	Jobs            []Job            `json:"jobs"`
+	Projects        []Project        `json:"projects"`
+	SkillCategories []SkillCategory  `json:"skillCategories"`
+	Location        string           `json:"location,omitempty"`
 }
 
 type CoverLetter struct {
@@ -90,6 +96,18 @@ type Job struct {
 	JobPoints    StringList `json:"jobPoints"`
 }
 
+// EducationEntry represents a single school/degree entry in the resume.
+type EducationEntry struct {
+	Institution string     `json:"institution"`
+	Degree      string     `json:"degree"`
+	Field       string     `json:"field,omitempty"`
+	StartDate   string     `json:"startDate"`
+	EndDate     string     `json:"endDate"`
+	Location    string     `json:"location"`
+	GPA         string     `json:"gpa,omitempty"`
+	Courses     StringList `json:"courses"`
+}
+
 // Project represents a single project entry in the resume.
 type Project struct {
 	ProjectTitle  string     `json:"projectTitle"`
@@ -113,14 +131,32 @@ type Application struct {
 	JobDescription    string       `json:"jobDescription"`
 	Resume            ResumeData   `json:"resume"`
 	CoverLetter       *CoverLetter `json:"coverLetter,omitempty"`
+	// Deadline (apply-by) and AppliedOn are YYYY-MM-DD dates.
+	Deadline   string           `json:"deadline,omitempty"`
+	AppliedOn  string           `json:"appliedOn,omitempty"`
+	Interviews []InterviewRound `json:"interviews"`
+	// Tags replace the application's tags on save; omit the field to leave them unchanged.
+	Tags []Tag `json:"tags"`
+	// ParentID is the application this one was cloned from. It is set by the server only.
+	ParentID string `json:"parentId,omitempty"`
+	// NextAction and NextActionDue are computed by the server and ignored on save.
+	NextAction    string     `json:"nextAction,omitempty"`
+	NextActionDue *time.Time `json:"nextActionDue,omitempty"`
+	// Version increments on every save; it is also sent as the ETag.
+	Version int64 `json:"version"`
 }
 
 // ApplicationSummary represents a brief overview of a job application for listing.
 type ApplicationSummary struct {
-	ID                string `json:"id"`
-	JobTitle          string `json:"jobTitle"`
-	Company           string `json:"company"`
-	ApplicationStatus string `json:"applicationStatus"`
+	ID                string     `json:"id"`
+	JobTitle          string     `json:"jobTitle"`
+	Company           string     `json:"company"`
+	ApplicationStatus string     `json:"applicationStatus"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty"`
+	Tags              []Tag      `json:"tags"`
+	ParentID          string     `json:"parentId,omitempty"`
 }
 
 // optimizeRequest is the payload for resume optimization.
@@ -140,100 +176,153 @@ type optimizeCoverLetterRequest struct {
 }
 
 func main() {
-	var err error
-	verifier, err = newJWTVerifierFromEnv()
+	slog.SetDefault(newLogger(os.Stderr))
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import-legacy" {
+		if err := runImportLegacyCommand(os.Args[2:]); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		if err := runOpenAPICommand(os.Args[2:]); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "purge-trash" {
+		if err := runPurgeTrashCommand(os.Args[2:]); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	backend, err := storeBackendFromEnv()
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
+	}
+	trashRetention, err := trashRetentionFromEnv()
+	if err != nil {
+		fatal(err)
+	}
+	if blobs, err = newBlobStoreFromEnv(); err != nil {
+		fatal(err)
+	}
+	if attachLimits, err = attachmentLimitsFromEnv(); err != nil {
+		fatal(err)
+	}
+	if offerFXRates, err = offerFXRatesFromEnv(); err != nil {
+		fatal(err)
 	}
 
-	// Try to connect to DB on boot, but don't crash-loop if the database is temporarily unreachable.
-	if s, err := newDBStore(context.Background()); err != nil {
-		log.Printf("DB not ready yet: %v", err)
+	// DEV_AUTH_USER_ID switches authentication off for offline development: any bearer token is
+	// accepted and every request acts as that user. It is refused unless the store is in memory,
+	// so it can never expose a real database, and must not be set on a deployed server.
+	if devUserID := strings.TrimSpace(os.Getenv("DEV_AUTH_USER_ID")); devUserID != "" {
+		if backend != storeBackendMemory {
+			fatal(errors.New("DEV_AUTH_USER_ID is only allowed with STORE_BACKEND=memory"))
+		}
+		slog.Warn("authentication disabled; all requests act as one user", "userId", devUserID)
+		verifier = newDevVerifier(devUserID)
+	} else {
+		verifier, err = newJWTVerifierFromEnv()
+		if err != nil {
+			fatal(err)
+		}
+	}
+
+	shutdownTimeout, err := shutdownTimeoutFromEnv()
+	if err != nil {
+		fatal(err)
+	}
+
+	// ctx is cancelled on SIGTERM (Fly stopping the machine) or SIGINT; background loops stop
+	// with it and the server drains in-flight requests.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	if backend == storeBackendMemory {
+		slog.Info("using in-memory store; data will not persist")
+		storeMu.Lock()
+		store = newMemoryStore()
+		storeMu.Unlock()
+	} else if s, err := newDBStore(ctx); err != nil {
+		// Try to connect to DB on boot, but don't crash-loop if the database is temporarily unreachable.
+		slog.Warn("DB not ready yet", "err", err)
 	} else {
 		storeMu.Lock()
 		store = s
 		storeMu.Unlock()
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.Write([]byte("ok\n"))
-	})
-
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "Welcome to the Job Application Backend!")
-	})
-
-	mux.HandleFunc("/api/profile", requireAuth(verifier, handleProfile))
-	mux.HandleFunc("/api/generate-pdf", requireAuth(verifier, handleGeneratePDF))
-	mux.HandleFunc("/api/preview-pdf", requireAuth(verifier, handlePreviewPDF))
-	mux.HandleFunc("/api/applications", requireAuth(verifier, handleApplications))
-	mux.HandleFunc("/api/applications/", requireAuth(verifier, handleApplicationByID)) // For GET, PUT, DELETE by ID
-	mux.HandleFunc("/api/optimize-resume", requireAuth(verifier, handleOptimizeResume))
-	mux.HandleFunc("/api/optimize-coverletter", requireAuth(verifier, handleOptimizeCoverLetter))
-	mux.HandleFunc("/api/github-projects", requireAuth(verifier, handleGithubProjects))
-
-	// Background DB connect/reconnect loop.
-	go func() {
-		t := time.NewTicker(5 * time.Second)
-		defer t.Stop()
-		for range t.C {
-			storeMu.RLock()
-			ready := store != nil
-			storeMu.RUnlock()
-			if ready {
-				continue
-			}
-			s, err := newDBStore(context.Background())
-			if err != nil {
-				log.Printf("DB still not ready: %v", err)
-				continue
-			}
-			storeMu.Lock()
-			// Double-check; another goroutine may have set it.
-			if store == nil {
-				store = s
-				s = nil
-				log.Printf("DB connection established")
-			}
-			storeMu.Unlock()
-			if s != nil {
-				s.Close()
-			}
-		}
-	}()
+	var background sync.WaitGroup
+	if backend == storeBackendPostgres {
+		background.Go(func() { runDBConnector(ctx) })
+	}
+	if trashRetention > 0 {
+		background.Go(func() { runTrashPurger(ctx, blobs, trashRetention) })
+	}
 
 	port := strings.TrimSpace(os.Getenv("PORT"))
 	if port == "" {
 		port = "8080"
 	}
-	fmt.Println("Server starting on port " + port + "...")
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	srv := newHTTPServer(":"+port, newRouter(verifier), requestCtx)
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	slog.Info("server starting", "port", port)
+
+	select {
+	case err := <-serveErr:
+		fatal(err)
+	case <-ctx.Done():
+	}
+	stop() // a second signal kills the process without waiting
+
+	slog.Info("shutting down; draining in-flight requests", "timeout", shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("requests still running after the shutdown timeout; cancelling them", "err", err)
+		cancelRequests()
+		srv.Close()
+	}
+	background.Wait()
+	if s := currentStore(); s != nil {
+		s.Close()
+	}
+	slog.Info("server stopped")
 }
 
 // handleOptimizeResume calls OpenAI to optimize the resume based on job details.
 func handleOptimizeResume(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	apiKey, err := geminiAPIKeyRequired(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	var req optimizeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateOptimizeRequest(req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	optimized, err := optimizeResumeWithAI(r.Context(), apiKey, req)
 	if err != nil {
-		http.Error(w, "Failed to optimize resume: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, aiUpstreamError(err))
 		return
 	}
 
@@ -242,26 +331,25 @@ func handleOptimizeResume(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleOptimizeCoverLetter(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	apiKey, err := geminiAPIKeyRequired(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	var req optimizeCoverLetterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateOptimizeCoverLetterRequest(req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	optimized, err := optimizeCoverLetterWithAI(r.Context(), apiKey, req)
 	if err != nil {
-		http.Error(w, "Failed to optimize cover letter: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, aiUpstreamError(err))
 		return
 	}
 
@@ -271,14 +359,9 @@ func handleOptimizeCoverLetter(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleGithubProjects(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Only GET method is allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	username := strings.TrimSpace(r.URL.Query().Get("username"))
 	if username == "" {
-		http.Error(w, "username query param is required", http.StatusBadRequest)
+		writeError(w, r, badRequest("username query param is required"))
 		return
 	}
 
@@ -289,134 +372,143 @@ func handleGithubProjects(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(cards)
 }
 
-// handleApplications handles GET to list all applications and POST to create a new application.
-func handleApplications(w http.ResponseWriter, r *http.Request) {
-	userID, err := userIDFromRequest(r)
+// handleListApplications handles GET /api/applications.
+func handleListApplications(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	query, err := parseApplicationListQuery(r.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
-	storeMu.RLock()
-	s := store
-	storeMu.RUnlock()
-	if s == nil {
-		http.Error(w, "database not ready", http.StatusServiceUnavailable)
+	page, err := s.ListApplicationSummaries(r.Context(), userID, query)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
+	// The body stays a plain array; the next page is advertised via X-Next-Cursor.
+	if page.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page.Items)
+}
 
-	switch r.Method {
-	case http.MethodGet:
-		summaries, err := s.ListApplicationSummaries(r.Context(), userID)
-		if err != nil {
-			http.Error(w, "Failed to list applications: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(summaries)
-
-	case http.MethodPost:
-		var app Application
-		if err := json.NewDecoder(r.Body).Decode(&app); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+// handleCreateApplication handles POST /api/applications.
+func handleCreateApplication(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	var app Application
+	if err := decodeJSONBody(w, r, &app); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := validateApplication(app); err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-		created, err := s.CreateApplication(r.Context(), userID, app)
-		if err != nil {
-			http.Error(w, "Failed to create application: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+	app.ParentID = "" // lineage is only recorded by POST /api/applications/{id}/clone
+	created, err := s.CreateApplication(r.Context(), userID, app)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(created)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(created.Version))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// handleGetApplication handles GET /api/applications/{id}.
+func handleGetApplication(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	app, err := s.GetApplication(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(app.Version))
+	json.NewEncoder(w).Encode(app)
 }
 
-// handleApplicationByID handles GET to retrieve a single application and PUT to update it.
-func handleApplicationByID(w http.ResponseWriter, r *http.Request) {
-	id := strings.TrimPrefix(r.URL.Path, "/api/applications/")
-	if id == "" || id == "applications/" { // Handle case where id is not provided after /api/applications/
-		http.Error(w, "Application ID is required", http.StatusBadRequest)
+// handleUpdateApplication handles PUT /api/applications/{id}. It requires If-Match; a stale
+// version gets a version_conflict error carrying the current copy.
+func handleUpdateApplication(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	id := r.PathValue("id")
+	ifMatch, err := ifMatchVersion(r, false)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
-	// Remove trailing slash if present
-	id = strings.TrimSuffix(id, "/")
 
-	userID, err := userIDFromRequest(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+	var updatedApp Application
+	if err := decodeJSONBody(w, r, &updatedApp); err != nil {
+		writeError(w, r, err)
 		return
 	}
-	storeMu.RLock()
-	s := store
-	storeMu.RUnlock()
-	if s == nil {
-		http.Error(w, "database not ready", http.StatusServiceUnavailable)
+	if err := validateApplication(updatedApp); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodGet:
-		app, err := s.GetApplication(r.Context(), userID, id)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, errNotFound) {
-				http.Error(w, "Application not found", http.StatusNotFound)
-			} else {
-				http.Error(w, "Failed to retrieve application: "+err.Error(), http.StatusInternalServerError)
-			}
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(app)
+	if updatedApp.ID != id {
+		writeError(w, r, badRequest("Application ID in URL and body do not match"))
+		return
+	}
 
-	case http.MethodPut:
-		var updatedApp Application
-		if err := json.NewDecoder(r.Body).Decode(&updatedApp); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	source, err := revisionSourceFromRequest(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-		if updatedApp.ID != id {
-			http.Error(w, "Application ID in URL and body do not match", http.StatusBadRequest)
+	updatedApp.Version = ifMatch
+	saved, err := s.UpdateApplication(r.Context(), userID, updatedApp, source)
+	if err != nil {
+		if errors.Is(err, errVersionConflict) {
+			writeApplicationConflict(w, r, s, userID, id)
 			return
 		}
+		writeError(w, r, orNotFound(err, "Application not found for update"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(saved.Version))
+	json.NewEncoder(w).Encode(saved)
+}
 
-		saved, err := s.UpdateApplication(r.Context(), userID, updatedApp)
-		if err != nil {
-			if errors.Is(err, errNotFound) || errors.Is(err, pgx.ErrNoRows) {
-				http.Error(w, "Application not found for update", http.StatusNotFound)
-				return
-			}
-			http.Error(w, "Failed to update application: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(saved)
-
-	case http.MethodDelete:
-		if err := s.DeleteApplication(r.Context(), userID, id); err != nil {
-			if errors.Is(err, errNotFound) || errors.Is(err, pgx.ErrNoRows) {
-				http.Error(w, "Application not found", http.StatusNotFound)
-				return
-			}
-			http.Error(w, "Failed to delete application: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(http.StatusNoContent)
+// handleDeleteApplication handles DELETE /api/applications/{id}, which moves the application to
+// the trash; see POST /api/applications/{id}/restore.
+func handleDeleteApplication(w http.ResponseWriter, r *http.Request, s Store, userID string) {
+	if err := s.DeleteApplication(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// writeApplicationConflict replies 412 with the server's current copy in details.current so the
+// client can reconcile.
+func writeApplicationConflict(w http.ResponseWriter, r *http.Request, s Store, userID, id string) {
+	current, err := s.GetApplication(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, orNotFound(err, "Application not found"))
+		return
 	}
+	w.Header().Set("ETag", formatETag(current.Version))
+	writeError(w, r, newAPIError(http.StatusPreconditionFailed, codeVersionConflict, errVersionConflict.Error()).
+		withDetails(map[string]any{"current": current}))
 }
 
 func normalizeOptimizedResume(res ResumeData, fallback ResumeData) ResumeData {
 	if res.RelevantCourses == nil {
 		res.RelevantCourses = []string{}
 	}
+	if len(res.Education) == 0 {
+		// Education is factual; never let the model drop it.
+		res.Education = fallback.Education
+	}
+	if res.Education == nil {
+		res.Education = []EducationEntry{}
+	}
 	if res.Jobs == nil {
 		res.Jobs = []Job{}
 	}
@@ -442,6 +534,11 @@ func normalizeOptimizedResume(res ResumeData, fallback ResumeData) ResumeData {
 	if res.Github == "" {
 		res.Github = fallback.Github
 	}
+	for i := range res.Education {
+		if res.Education[i].Courses == nil {
+			res.Education[i].Courses = []string{}
+		}
+	}
 	for i := range res.Jobs {
 		if res.Jobs[i].JobPoints == nil {
 			res.Jobs[i].JobPoints = []string{}
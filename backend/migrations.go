@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migrations are embedded into the binary. Each version has an up file (NNN_name.sql)
+// and an optional down file (NNN_name.down.sql).
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migrationLockID is an arbitrary key for pg_advisory_lock so only one instance migrates at a time.
+const migrationLockID = 727274001
+
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type migrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*migration{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		base := strings.TrimSuffix(e.Name(), ".sql")
+		isDown := strings.HasSuffix(base, ".down")
+		base = strings.TrimSuffix(base, ".down")
+
+		versionPart, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration filename %q (want NNN_name.sql)", e.Name())
+		}
+		version, err := strconv.Atoi(versionPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", e.Name(), err)
+		}
+
+		body, err := fs.ReadFile(migrationFS, path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has mismatched names %q and %q", version, m.Name, name)
+		}
+		if isDown {
+			m.Down = string(body)
+		} else {
+			m.Up = string(body)
+		}
+	}
+
+	out := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up file", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func ensureMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `
+		create table if not exists schema_migrations (
+		  version integer primary key,
+		  name text not null,
+		  applied_at timestamptz not null default now()
+		)
+	`)
+	return err
+}
+
+func appliedMigrations(ctx context.Context, pool *pgxpool.Pool) (map[int]time.Time, error) {
+	rows, err := pool.Query(ctx, `select version, applied_at from schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int]time.Time{}
+	for rows.Next() {
+		var v int
+		var at time.Time
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, err
+		}
+		out[v] = at
+	}
+	return out, rows.Err()
+}
+
+// withMigrationLock runs fn while holding a session-level advisory lock on a dedicated connection.
+func withMigrationLock(ctx context.Context, pool *pgxpool.Pool, fn func() error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `select pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `select pg_advisory_unlock($1)`, migrationLockID)
+
+	if err := ensureMigrationsTable(ctx, pool); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// migrateUp applies every pending migration in version order. It returns the versions applied.
+func migrateUp(ctx context.Context, pool *pgxpool.Pool) ([]int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []int
+	err = withMigrationLock(ctx, pool, func() error {
+		applied, err := appliedMigrations(ctx, pool)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := applyMigration(ctx, pool, m.Version, m.Name, m.Up, true); err != nil {
+				return err
+			}
+			ran = append(ran, m.Version)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// migrateDown reverts the most recent steps applied migrations. It returns the versions reverted.
+func migrateDown(ctx context.Context, pool *pgxpool.Pool, steps int) ([]int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []int
+	err = withMigrationLock(ctx, pool, func() error {
+		applied, err := appliedMigrations(ctx, pool)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(m.Down) == "" {
+				return fmt.Errorf("migration %d (%s) has no down file", m.Version, m.Name)
+			}
+			if err := applyMigration(ctx, pool, m.Version, m.Name, m.Down, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, m.Version)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func applyMigration(ctx context.Context, pool *pgxpool.Pool, version int, name, sql string, up bool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("migration %d (%s) failed: %w", version, name, err)
+	}
+	if up {
+		_, err = tx.Exec(ctx, `insert into schema_migrations (version, name, applied_at) values ($1, $2, now())`, version, name)
+	} else {
+		_, err = tx.Exec(ctx, `delete from schema_migrations where version = $1`, version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func migrationStatuses(ctx context.Context, pool *pgxpool.Pool) ([]migrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(ctx, pool); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]migrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		st := migrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
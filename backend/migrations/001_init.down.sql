@@ -0,0 +1,2 @@
+drop table if exists applications;
+drop table if exists profiles;
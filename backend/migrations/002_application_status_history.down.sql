@@ -0,0 +1,4 @@
+drop table if exists application_status_history;
+
+alter table applications
+  drop constraint if exists applications_status_check;
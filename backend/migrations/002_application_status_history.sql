@@ -0,0 +1,31 @@
+-- Application status pipeline + transition history
+
+update applications
+set application_status = lower(trim(application_status));
+
+update applications
+set application_status = 'applied'
+where application_status not in ('saved', 'applied', 'screening', 'interview', 'offer', 'accepted', 'rejected', 'withdrawn');
+
+alter table applications
+  drop constraint if exists applications_status_check;
+alter table applications
+  add constraint applications_status_check
+  check (application_status in ('saved', 'applied', 'screening', 'interview', 'offer', 'accepted', 'rejected', 'withdrawn'));
+
+create table if not exists application_status_history (
+  id bigserial primary key,
+  application_id uuid not null references applications(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  from_status text,
+  to_status text not null,
+  changed_at timestamptz not null default now()
+);
+
+create index if not exists application_status_history_application_id_idx on application_status_history (application_id, changed_at);
+
+-- Seed one entry per existing application so every application has a starting point.
+insert into application_status_history (application_id, user_id, from_status, to_status, changed_at)
+select a.id, a.user_id, null, a.application_status, a.created_at
+from applications a
+where not exists (select 1 from application_status_history h where h.application_id = a.id);
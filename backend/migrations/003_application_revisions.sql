@@ -0,0 +1,22 @@
+-- Append-only resume / cover letter revisions per application
+
+create table if not exists application_revisions (
+  id bigserial primary key,
+  application_id uuid not null references applications(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  source text not null default 'manual',
+  restored_from bigint references application_revisions(id) on delete set null,
+  resume jsonb not null default '{}'::jsonb,
+  cover_letter jsonb,
+  created_at timestamptz not null default now(),
+  constraint application_revisions_source_check
+    check (source in ('manual', 'ai_resume', 'ai_cover_letter', 'github_import', 'restore'))
+);
+
+create index if not exists application_revisions_application_id_idx on application_revisions (application_id, id desc);
+
+-- Snapshot existing content so the first edit after this migration is recoverable.
+insert into application_revisions (application_id, user_id, source, resume, cover_letter, created_at)
+select a.id, a.user_id, 'manual', a.resume, a.cover_letter, a.updated_at
+from applications a
+where not exists (select 1 from application_revisions r where r.application_id = a.id);
@@ -0,0 +1,7 @@
+update application_revisions set source = 'manual' where source = 'import';
+
+alter table application_revisions
+  drop constraint if exists application_revisions_source_check;
+alter table application_revisions
+  add constraint application_revisions_source_check
+  check (source in ('manual', 'ai_resume', 'ai_cover_letter', 'github_import', 'restore'));
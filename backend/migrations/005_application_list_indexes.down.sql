@@ -0,0 +1,7 @@
+drop index if exists applications_search_trgm_idx;
+drop index if exists applications_user_status_idx;
+drop index if exists applications_user_company_idx;
+drop index if exists applications_user_created_idx;
+drop index if exists applications_user_updated_idx;
+
+create index if not exists applications_user_id_updated_at_idx on applications (user_id, updated_at desc);
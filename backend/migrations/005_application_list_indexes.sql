@@ -0,0 +1,12 @@
+-- Indexes backing filtered / sorted / paginated application lists
+
+create extension if not exists pg_trgm;
+
+drop index if exists applications_user_id_updated_at_idx;
+create index if not exists applications_user_updated_idx on applications (user_id, updated_at desc, id desc);
+create index if not exists applications_user_created_idx on applications (user_id, created_at desc, id desc);
+create index if not exists applications_user_company_idx on applications (user_id, lower(company), id);
+create index if not exists applications_user_status_idx on applications (user_id, application_status);
+
+create index if not exists applications_search_trgm_idx on applications
+  using gin ((job_title || ' ' || company || ' ' || job_description) gin_trgm_ops);
@@ -0,0 +1,2 @@
+alter table profiles drop column if exists version;
+alter table applications drop column if exists version;
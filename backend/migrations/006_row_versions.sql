@@ -0,0 +1,4 @@
+-- Version counters for optimistic concurrency (sent to clients as ETags)
+
+alter table applications add column if not exists version bigint not null default 1;
+alter table profiles add column if not exists version bigint not null default 1;
@@ -0,0 +1,3 @@
+delete from applications where deleted_at is not null;
+drop index if exists applications_deleted_at_idx;
+alter table applications drop column if exists deleted_at;
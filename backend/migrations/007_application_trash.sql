@@ -0,0 +1,5 @@
+-- Soft delete: trashed applications keep their data until purged
+
+alter table applications add column if not exists deleted_at timestamptz;
+
+create index if not exists applications_deleted_at_idx on applications (deleted_at) where deleted_at is not null;
@@ -0,0 +1,3 @@
+alter table applications drop column if exists interviews;
+alter table applications drop column if exists applied_on;
+alter table applications drop column if exists deadline;
@@ -0,0 +1,5 @@
+-- Deadlines, applied-on dates and interview rounds for follow-up reminders
+
+alter table applications add column if not exists deadline date;
+alter table applications add column if not exists applied_on date;
+alter table applications add column if not exists interviews jsonb not null default '[]'::jsonb;
@@ -0,0 +1 @@
+drop table if exists calendar_feed_tokens;
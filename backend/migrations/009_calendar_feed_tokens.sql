@@ -0,0 +1,8 @@
+-- Revocable tokens for subscribable iCalendar feeds (calendar clients can't send a bearer token).
+-- Only a SHA-256 hash of the token is stored.
+
+create table if not exists calendar_feed_tokens (
+  user_id uuid primary key references auth.users(id) on delete cascade,
+  token_hash bytea not null unique,
+  created_at timestamptz not null default now()
+);
@@ -0,0 +1,2 @@
+drop table if exists application_contacts;
+drop table if exists contacts;
@@ -0,0 +1,32 @@
+-- Recruiters / hiring managers, linked many-to-many with applications
+
+create table if not exists contacts (
+  id uuid primary key default gen_random_uuid(),
+  user_id uuid not null references auth.users(id) on delete cascade,
+  name text not null,
+  email text not null default '',
+  phone text not null default '',
+  linkedin text not null default '',
+  company text not null default '',
+  role text not null default '',
+  notes text not null default '',
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create index if not exists contacts_user_name_idx on contacts (user_id, lower(name));
+
+create table if not exists application_contacts (
+  application_id uuid not null references applications(id) on delete cascade,
+  contact_id uuid not null references contacts(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  is_primary boolean not null default false,
+  created_at timestamptz not null default now(),
+  primary key (application_id, contact_id)
+);
+
+create index if not exists application_contacts_contact_idx on application_contacts (contact_id);
+
+-- At most one primary contact per application.
+create unique index if not exists application_contacts_primary_idx
+  on application_contacts (application_id) where is_primary;
@@ -0,0 +1,2 @@
+drop table if exists application_tags;
+drop table if exists tags;
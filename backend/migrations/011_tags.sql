@@ -0,0 +1,20 @@
+-- User-defined, colored tags on applications
+
+create table if not exists tags (
+  id bigserial primary key,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  name text not null,
+  color text not null,
+  created_at timestamptz not null default now()
+);
+
+create unique index if not exists tags_user_name_idx on tags (user_id, lower(name));
+
+create table if not exists application_tags (
+  application_id uuid not null references applications(id) on delete cascade,
+  tag_id bigint not null references tags(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  primary key (application_id, tag_id)
+);
+
+create index if not exists application_tags_tag_idx on application_tags (tag_id);
@@ -0,0 +1,2 @@
+drop index if exists applications_parent_idx;
+alter table applications drop column if exists parent_id;
@@ -0,0 +1,5 @@
+-- Lineage for cloned applications
+
+alter table applications add column if not exists parent_id uuid references applications(id) on delete set null;
+
+create index if not exists applications_parent_idx on applications (parent_id) where parent_id is not null;
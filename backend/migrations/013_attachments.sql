@@ -0,0 +1,17 @@
+-- Files attached to applications. The bytes live in the blob store (local disk or S3); this
+-- table holds metadata and backs per-user quotas.
+
+create table if not exists attachments (
+  id uuid primary key default gen_random_uuid(),
+  application_id uuid not null references applications(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  filename text not null,
+  content_type text not null,
+  size_bytes bigint not null check (size_bytes >= 0),
+  sha256 text not null,
+  blob_key text not null unique,
+  created_at timestamptz not null default now()
+);
+
+create index if not exists attachments_application_idx on attachments (application_id, created_at);
+create index if not exists attachments_user_idx on attachments (user_id);
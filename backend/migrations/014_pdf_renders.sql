@@ -0,0 +1,21 @@
+-- Generated resume/cover letter PDFs kept as a record of what was sent. The PDFs live in the
+-- blob store; the row keeps their hashes and the exact input they were rendered from.
+
+create table if not exists pdf_renders (
+  id uuid primary key default gen_random_uuid(),
+  application_id uuid not null references applications(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  application_version bigint not null,
+  input jsonb not null,
+  resume_filename text not null,
+  resume_sha256 text not null,
+  resume_size bigint not null,
+  resume_blob_key text not null unique,
+  cover_filename text not null,
+  cover_sha256 text not null,
+  cover_size bigint not null,
+  cover_blob_key text not null unique,
+  created_at timestamptz not null default now()
+);
+
+create index if not exists pdf_renders_application_idx on pdf_renders (application_id, created_at desc);
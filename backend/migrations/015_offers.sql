@@ -0,0 +1,26 @@
+-- Compensation offers, one per application, with an append-only negotiation history
+
+create table if not exists offers (
+  id uuid primary key default gen_random_uuid(),
+  application_id uuid not null unique references applications(id) on delete cascade,
+  user_id uuid not null references auth.users(id) on delete cascade,
+  currency char(3) not null,
+  base_salary numeric(16, 2) not null check (base_salary >= 0),
+  base_period text not null check (base_period in ('year', 'month', 'week', 'hour')),
+  hours_per_week numeric(5, 2) not null default 0,
+  signing_bonus numeric(16, 2) not null default 0 check (signing_bonus >= 0),
+  annual_bonus numeric(16, 2) not null default 0 check (annual_bonus >= 0),
+  equity_value numeric(16, 2) not null default 0 check (equity_value >= 0),
+  equity_vesting_years numeric(4, 2) not null default 0,
+  benefits text not null default '',
+  benefits_value numeric(16, 2) not null default 0 check (benefits_value >= 0),
+  location text not null default '',
+  start_date date,
+  expires_on date,
+  notes text not null default '',
+  negotiation jsonb not null default '[]'::jsonb,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create index if not exists offers_user_idx on offers (user_id);
@@ -0,0 +1,8 @@
+-- Short-lived, single-use tokens confirming DELETE /api/account. Only a SHA-256 hash is stored.
+
+create table if not exists account_deletion_tokens (
+  user_id uuid primary key references auth.users(id) on delete cascade,
+  token_hash bytea not null,
+  expires_at timestamptz not null,
+  created_at timestamptz not null default now()
+);
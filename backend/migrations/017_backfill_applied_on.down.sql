@@ -0,0 +1,2 @@
+-- The backfilled dates can't be told apart from ones the user set, so they are kept.
+select 1;
@@ -0,0 +1,13 @@
+-- Backfill applied_on for applications that existed before 008 added it
+
+-- Applications past "saved" were applied to the day they first moved to "applied", or, for open
+-- applications that never passed through it, the day they were created.
+update applications a
+set applied_on = coalesce(
+  (select min(h.changed_at)::date
+   from application_status_history h
+   where h.application_id = a.id and h.to_status = 'applied'),
+  case when a.application_status in ('accepted', 'rejected', 'withdrawn') then null else a.created_at::date end
+)
+where a.applied_on is null
+  and a.application_status <> 'saved';
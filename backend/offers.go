@@ -0,0 +1,339 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	basePeriodYear  = "year"
+	basePeriodMonth = "month"
+	basePeriodWeek  = "week"
+	basePeriodHour  = "hour"
+
+	defaultHoursPerWeek       = 40
+	defaultEquityVestingYears = 4
+
+	negotiationByCandidate = "candidate"
+	negotiationByEmployer  = "employer"
+)
+
+var (
+	errInvalidOffer = errors.New("invalid offer")
+	errOfferCompare = errors.New("cannot compare offers")
+)
+
+// zeroDecimalCurrencies have no minor unit, so their amounts are whole numbers.
+var zeroDecimalCurrencies = map[string]bool{
+	"CLP": true, "ISK": true, "JPY": true, "KRW": true, "PYG": true,
+	"UGX": true, "VND": true, "XAF": true, "XOF": true,
+}
+
+// Offer is the compensation package offered for an application. Amounts are in Currency;
+// BaseSalary is per BasePeriod and every other amount is as labelled.
+type Offer struct {
+	ID            string `json:"id"`
+	ApplicationID string `json:"applicationId"`
+	// JobTitle and Company come from the application and are ignored on save.
+	JobTitle string `json:"jobTitle"`
+	Company  string `json:"company"`
+
+	Currency   string  `json:"currency"` // ISO 4217, e.g. USD
+	BaseSalary float64 `json:"baseSalary"`
+	BasePeriod string  `json:"basePeriod"` // year, month, week or hour
+	// HoursPerWeek annualizes hourly pay; it defaults to 40.
+	HoursPerWeek float64 `json:"hoursPerWeek,omitempty"`
+	SigningBonus float64 `json:"signingBonus"`
+	// AnnualBonus is the expected (target) yearly bonus.
+	AnnualBonus float64 `json:"annualBonus"`
+	// EquityValue is the total grant value, vesting evenly over EquityVestingYears (default 4).
+	EquityValue        float64 `json:"equityValue"`
+	EquityVestingYears float64 `json:"equityVestingYears,omitempty"`
+	Benefits           string  `json:"benefits"`
+	// BenefitsValue is an optional yearly estimate of the benefits (retirement match, stipends...).
+	BenefitsValue float64 `json:"benefitsValue"`
+	Location      string  `json:"location"`
+	StartDate     string  `json:"startDate,omitempty"` // YYYY-MM-DD
+	ExpiresOn     string  `json:"expiresOn,omitempty"` // YYYY-MM-DD; when the offer must be answered
+	Notes         string  `json:"notes"`
+
+	// Negotiation is the append-only history of counter-offers and replies; it is ignored on save.
+	Negotiation []NegotiationEvent `json:"negotiation"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+// NegotiationEvent is one step of an offer negotiation, e.g. a counter-offer.
+type NegotiationEvent struct {
+	At time.Time `json:"at"`
+	By string    `json:"by"` // candidate or employer
+	// Amount is an optional figure under discussion (typically base salary), in the offer's currency.
+	Amount *float64 `json:"amount,omitempty"`
+	Note   string   `json:"note"`
+}
+
+// normalizeOffer validates an offer in place, filling defaults and rounding amounts to the
+// currency's minor unit.
+func normalizeOffer(o *Offer) error {
+	o.Currency = strings.ToUpper(strings.TrimSpace(o.Currency))
+	if !isCurrencyCode(o.Currency) {
+		return fmt.Errorf("%w: currency must be a 3-letter ISO 4217 code", errInvalidOffer)
+	}
+
+	o.BasePeriod = strings.ToLower(strings.TrimSpace(o.BasePeriod))
+	switch o.BasePeriod {
+	case "":
+		o.BasePeriod = basePeriodYear
+	case basePeriodYear, basePeriodMonth, basePeriodWeek, basePeriodHour:
+	default:
+		return fmt.Errorf("%w: basePeriod must be year, month, week or hour", errInvalidOffer)
+	}
+	if o.BasePeriod == basePeriodHour {
+		if o.HoursPerWeek == 0 {
+			o.HoursPerWeek = defaultHoursPerWeek
+		}
+		if o.HoursPerWeek < 0 || o.HoursPerWeek > 168 {
+			return fmt.Errorf("%w: hoursPerWeek must be between 1 and 168", errInvalidOffer)
+		}
+	} else {
+		o.HoursPerWeek = 0
+	}
+
+	amounts := []struct {
+		name  string
+		value *float64
+	}{
+		{"baseSalary", &o.BaseSalary},
+		{"signingBonus", &o.SigningBonus},
+		{"annualBonus", &o.AnnualBonus},
+		{"equityValue", &o.EquityValue},
+		{"benefitsValue", &o.BenefitsValue},
+	}
+	for _, a := range amounts {
+		if math.IsNaN(*a.value) || math.IsInf(*a.value, 0) || *a.value < 0 {
+			return fmt.Errorf("%w: %s must be zero or more", errInvalidOffer, a.name)
+		}
+		*a.value = roundMoney(*a.value, o.Currency)
+	}
+	if o.BaseSalary == 0 {
+		return fmt.Errorf("%w: baseSalary is required", errInvalidOffer)
+	}
+
+	if o.EquityValue > 0 && o.EquityVestingYears == 0 {
+		o.EquityVestingYears = defaultEquityVestingYears
+	}
+	if o.EquityVestingYears < 0 || o.EquityVestingYears > 10 {
+		return fmt.Errorf("%w: equityVestingYears must be between 0 and 10", errInvalidOffer)
+	}
+
+	var err error
+	if o.StartDate, err = normalizeDate("startDate", o.StartDate); err != nil {
+		return fmt.Errorf("%w: startDate must be a YYYY-MM-DD date", errInvalidOffer)
+	}
+	if o.ExpiresOn, err = normalizeDate("expiresOn", o.ExpiresOn); err != nil {
+		return fmt.Errorf("%w: expiresOn must be a YYYY-MM-DD date", errInvalidOffer)
+	}
+	o.Benefits = strings.TrimSpace(o.Benefits)
+	o.Location = strings.TrimSpace(o.Location)
+	return nil
+}
+
+// normalizeNegotiationEvent validates an event against the offer's currency.
+func normalizeNegotiationEvent(e *NegotiationEvent, currency string, now time.Time) error {
+	e.By = strings.ToLower(strings.TrimSpace(e.By))
+	if e.By != negotiationByCandidate && e.By != negotiationByEmployer {
+		return fmt.Errorf("%w: by must be candidate or employer", errInvalidOffer)
+	}
+	e.Note = strings.TrimSpace(e.Note)
+	if e.Amount != nil {
+		if math.IsNaN(*e.Amount) || math.IsInf(*e.Amount, 0) || *e.Amount < 0 {
+			return fmt.Errorf("%w: amount must be zero or more", errInvalidOffer)
+		}
+		v := roundMoney(*e.Amount, currency)
+		e.Amount = &v
+	}
+	if e.Note == "" && e.Amount == nil {
+		return fmt.Errorf("%w: a negotiation event needs a note or an amount", errInvalidOffer)
+	}
+	if e.At.IsZero() {
+		e.At = now
+	}
+	e.At = e.At.UTC()
+	return nil
+}
+
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func roundMoney(v float64, currency string) float64 {
+	if zeroDecimalCurrencies[currency] {
+		return math.Round(v)
+	}
+	return math.Round(v*100) / 100
+}
+
+// AnnualizedComp breaks an offer's recurring yearly value into its parts.
+type AnnualizedComp struct {
+	Base     float64 `json:"base"`
+	Bonus    float64 `json:"bonus"`
+	Equity   float64 `json:"equity"`
+	Benefits float64 `json:"benefits"`
+	Total    float64 `json:"total"`
+}
+
+// OfferComparison is one offer normalized to the comparison currency. Rank 1 has the highest
+// annualized total.
+type OfferComparison struct {
+	Rank             int            `json:"rank"`
+	OfferID          string         `json:"offerId"`
+	ApplicationID    string         `json:"applicationId"`
+	JobTitle         string         `json:"jobTitle"`
+	Company          string         `json:"company"`
+	Location         string         `json:"location"`
+	StartDate        string         `json:"startDate,omitempty"`
+	ExpiresOn        string         `json:"expiresOn,omitempty"`
+	OriginalCurrency string         `json:"originalCurrency"`
+	ExchangeRate     float64        `json:"exchangeRate"` // comparison currency per unit of OriginalCurrency
+	Annualized       AnnualizedComp `json:"annualized"`
+	SigningBonus     float64        `json:"signingBonus"`
+	// FirstYearTotal is the annualized total plus the signing bonus.
+	FirstYearTotal float64 `json:"firstYearTotal"`
+}
+
+// OfferComparisonResult is the response of GET /api/offers/compare.
+type OfferComparisonResult struct {
+	Currency string            `json:"currency"`
+	Offers   []OfferComparison `json:"offers"`
+}
+
+// annualBase converts the base salary to a yearly amount.
+func (o Offer) annualBase() float64 {
+	switch o.BasePeriod {
+	case basePeriodMonth:
+		return o.BaseSalary * 12
+	case basePeriodWeek:
+		return o.BaseSalary * 52
+	case basePeriodHour:
+		hours := o.HoursPerWeek
+		if hours == 0 {
+			hours = defaultHoursPerWeek
+		}
+		return o.BaseSalary * hours * 52
+	default:
+		return o.BaseSalary
+	}
+}
+
+// compareOffers annualizes offers into currency using rates and ranks them by annualized total.
+func compareOffers(offers []Offer, currency string, rates fxRates) (OfferComparisonResult, error) {
+	if currency == "" && len(offers) > 0 {
+		currency = offers[0].Currency
+	}
+	out := OfferComparisonResult{Currency: currency, Offers: []OfferComparison{}}
+	for _, o := range offers {
+		rate, err := rates.convert(o.Currency, currency)
+		if err != nil {
+			return OfferComparisonResult{}, err
+		}
+		conv := func(v float64) float64 { return roundMoney(v*rate, currency) }
+
+		a := AnnualizedComp{
+			Base:     conv(o.annualBase()),
+			Bonus:    conv(o.AnnualBonus),
+			Benefits: conv(o.BenefitsValue),
+		}
+		if o.EquityValue > 0 && o.EquityVestingYears > 0 {
+			a.Equity = conv(o.EquityValue / o.EquityVestingYears)
+		}
+		a.Total = roundMoney(a.Base+a.Bonus+a.Equity+a.Benefits, currency)
+		signing := conv(o.SigningBonus)
+
+		out.Offers = append(out.Offers, OfferComparison{
+			OfferID:          o.ID,
+			ApplicationID:    o.ApplicationID,
+			JobTitle:         o.JobTitle,
+			Company:          o.Company,
+			Location:         o.Location,
+			StartDate:        o.StartDate,
+			ExpiresOn:        o.ExpiresOn,
+			OriginalCurrency: o.Currency,
+			ExchangeRate:     rate,
+			Annualized:       a,
+			SigningBonus:     signing,
+			FirstYearTotal:   roundMoney(a.Total+signing, currency),
+		})
+	}
+	sort.SliceStable(out.Offers, func(i, j int) bool {
+		return out.Offers[i].Annualized.Total > out.Offers[j].Annualized.Total
+	})
+	for i := range out.Offers {
+		out.Offers[i].Rank = i + 1
+	}
+	return out, nil
+}
+
+// fxRates values currencies against a common reference: 1 unit of a currency is worth rates[code]
+// reference units. Only ratios matter, so any reference currency works.
+type fxRates map[string]float64
+
+// convert returns how many units of to one unit of from is worth.
+func (r fxRates) convert(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	f, fok := r[from]
+	t, tok := r[to]
+	if !fok || !tok {
+		missing := from
+		if fok {
+			missing = to
+		}
+		return 0, fmt.Errorf("%w: no exchange rate for %s (needed to convert %s to %s; pass rates=%s:<value> or set OFFER_FX_RATES)", errOfferCompare, missing, from, to, missing)
+	}
+	return f / t, nil
+}
+
+// parseFXRates reads "EUR:1.08,GBP:1.27,USD:1" (":" or "=" separated) into rates.
+func parseFXRates(raw string, into fxRates) error {
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(part, ":")
+		if !ok {
+			code, value, ok = strings.Cut(part, "=")
+		}
+		code = strings.ToUpper(strings.TrimSpace(code))
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || !isCurrencyCode(code) || err != nil || rate <= 0 || math.IsInf(rate, 0) {
+			return fmt.Errorf("%w: invalid exchange rate %q (want CODE:value)", errOfferCompare, part)
+		}
+		into[code] = rate
+	}
+	return nil
+}
+
+// offerFXRatesFromEnv reads default exchange rates from OFFER_FX_RATES.
+func offerFXRatesFromEnv() (fxRates, error) {
+	rates := fxRates{}
+	if err := parseFXRates(os.Getenv("OFFER_FX_RATES"), rates); err != nil {
+		return nil, fmt.Errorf("invalid OFFER_FX_RATES: %w", err)
+	}
+	return rates, nil
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	_ "embed"
+)
+
+// openAPISpec is the generated document served at /api/openapi.json. Regenerate it (and the
+// TypeScript client) with `go run . openapi` after changing a route or a request/response type;
+// TestOpenAPISpecUpToDate fails until you do.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// handleOpenAPI handles GET /api/openapi.json.
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(openAPISpec)
+}
+
+// apiOperation describes one route for the OpenAPI document. Request and Response are zero
+// values of the Go types the handler decodes and encodes; their schemas come from reflection.
+type apiOperation struct {
+	Method  string
+	Path    string // the ServeMux pattern's path, e.g. /api/applications/{id}
+	ID      string // operationId, also the typed client's method name
+	Tag     string
+	Summary string
+	Public  bool // no bearer token
+
+	Query   []apiParam
+	Headers []apiParam
+
+	Request      any      // JSON request body
+	RequestTypes []string // non-JSON request body media types
+	OptionalBody bool
+
+	Response        any      // JSON response body
+	ResponseTypes   []string // non-JSON response media types
+	ResponseHeaders []apiParam
+	Status          []int // success statuses; 200 by default
+}
+
+// apiParam is a query parameter or header. Type is a JSON Schema type; string by default.
+type apiParam struct {
+	Name        string
+	Description string
+	Required    bool
+	Type        string
+}
+
+const (
+	mediaZip         = "application/zip"
+	mediaPDF         = "application/pdf"
+	mediaCalendar    = "text/calendar"
+	mediaText        = "text/plain"
+	mediaMultipart   = "multipart/form-data"
+	mediaOctetStream = "application/octet-stream"
+)
+
+var (
+	headerIfMatch      = apiParam{Name: "If-Match", Description: "The ETag of the version being replaced.", Required: true}
+	headerGeminiKey    = apiParam{Name: "X-Gemini-Api-Key", Description: "Gemini API key; overrides the server's GEMINI_API_KEY."}
+	responseHeaderETag = apiParam{Name: "ETag", Description: "The resource version, for If-Match."}
+	queryDoc           = apiParam{Name: "doc", Description: "resume or cover."}
+
+	// The profile is a free-form JSON document.
+	profileDocument = json.RawMessage(nil)
+
+	statusNoContent       = []int{http.StatusNoContent}
+	statusCreated         = []int{http.StatusCreated}
+	statusCreatedOrUpdate = []int{http.StatusOK, http.StatusCreated}
+)
+
+// apiOperations lists every route registered by newAPIRouter; TestOpenAPIRoutesMatchRouter
+// checks that the two agree.
+var apiOperations = []apiOperation{
+	{Method: "GET", Path: "/", ID: "getWelcome", Tag: "meta", Summary: "Plain-text greeting.", Public: true, ResponseTypes: []string{mediaText}},
+	{Method: "GET", Path: "/healthz", ID: "getHealth", Tag: "meta", Summary: "Liveness check.", Public: true, ResponseTypes: []string{mediaText}},
+	{Method: "GET", Path: "/api/openapi.json", ID: "getOpenAPI", Tag: "meta", Summary: "This document.", Public: true, Response: map[string]any(nil)},
+	{Method: "GET", Path: "/calendar/{token}", ID: "getCalendarFeed", Tag: "calendar", Summary: "Calendar subscription feed; token is the feed token followed by .ics.", Public: true, ResponseTypes: []string{mediaCalendar}},
+
+	{Method: "GET", Path: "/api/profile", ID: "getProfile", Tag: "profile", Summary: "The user's profile document.", Response: profileDocument, ResponseHeaders: []apiParam{responseHeaderETag}},
+	{Method: "PUT", Path: "/api/profile", ID: "putProfile", Tag: "profile", Summary: "Replace the profile. Send If-Match, or If-None-Match: * to create the first one.",
+		Headers:  []apiParam{{Name: "If-Match", Description: "The ETag of the version being replaced."}, {Name: "If-None-Match", Description: "* to create the first profile."}},
+		Request:  profileDocument,
+		Response: profileDocument, ResponseHeaders: []apiParam{responseHeaderETag}},
+
+	{Method: "POST", Path: "/api/generate-pdf", ID: "generatePDF", Tag: "pdf", Summary: "Render the resume and cover letter into a ZIP of two PDFs.",
+		Query:   []apiParam{{Name: "save", Description: "1 to keep the PDFs as a render of the saved application."}},
+		Request: Application{}, ResponseTypes: []string{mediaZip},
+		ResponseHeaders: []apiParam{{Name: "X-Render-Id", Description: "The saved render's id, with save=1."}}},
+	{Method: "POST", Path: "/api/preview-pdf", ID: "previewPDF", Tag: "pdf", Summary: "Render one document as a PDF.",
+		Query: []apiParam{queryDoc}, Request: Application{}, ResponseTypes: []string{mediaPDF}},
+	{Method: "POST", Path: "/api/optimize-resume", ID: "optimizeResume", Tag: "ai", Summary: "Tailor the resume to the job with Gemini.",
+		Headers: []apiParam{headerGeminiKey}, Request: optimizeRequest{}, Response: ResumeData{}},
+	{Method: "POST", Path: "/api/optimize-coverletter", ID: "optimizeCoverLetter", Tag: "ai", Summary: "Write cover letter paragraphs with Gemini, separated by \" | \".",
+		Headers: []apiParam{headerGeminiKey}, Request: optimizeCoverLetterRequest{}, ResponseTypes: []string{mediaText}},
+	{Method: "GET", Path: "/api/github-projects", ID: "listGithubProjects", Tag: "ai", Summary: "Public GitHub repositories summarized as resume projects.",
+		Query:    []apiParam{{Name: "username", Description: "GitHub username.", Required: true}, {Name: "debugAI", Description: "1 to include AI errors per project."}},
+		Headers:  []apiParam{headerGeminiKey},
+		Response: []ProjectCard(nil)},
+
+	{Method: "GET", Path: "/api/applications", ID: "listApplications", Tag: "applications", Summary: "List applications; follow X-Next-Cursor for more pages.",
+		Query: []apiParam{
+			{Name: "status", Description: "Comma-separated statuses."},
+			{Name: "company", Description: "Company name filter."},
+			{Name: "q", Description: "Searches title, company and job description."},
+			{Name: "sort", Description: "updated, created or company."},
+			{Name: "order", Description: "asc or desc."},
+			{Name: "limit", Description: "Page size, default 50, max 200.", Type: "integer"},
+			{Name: "cursor", Description: "X-Next-Cursor of the previous page."},
+			{Name: "tag", Description: "Comma-separated tag names; all must match."},
+			{Name: "trash", Description: "1 to list trashed applications."},
+		},
+		Response:        []ApplicationSummary(nil),
+		ResponseHeaders: []apiParam{{Name: "X-Next-Cursor", Description: "Cursor of the next page, if any."}}},
+	{Method: "POST", Path: "/api/applications", ID: "createApplication", Tag: "applications", Summary: "Create an application.",
+		Request: Application{}, Response: Application{}, ResponseHeaders: []apiParam{responseHeaderETag}, Status: statusCreated},
+	{Method: "GET", Path: "/api/applications/{id}", ID: "getApplication", Tag: "applications", Summary: "Get an application.",
+		Response: Application{}, ResponseHeaders: []apiParam{responseHeaderETag}},
+	{Method: "PUT", Path: "/api/applications/{id}", ID: "updateApplication", Tag: "applications", Summary: "Replace an application. A stale If-Match returns version_conflict with details.current.",
+		Headers:  []apiParam{headerIfMatch, {Name: "X-Revision-Source", Description: "manual, ai_resume, ai_cover_letter or github_import."}},
+		Request:  Application{},
+		Response: Application{}, ResponseHeaders: []apiParam{responseHeaderETag}},
+	{Method: "DELETE", Path: "/api/applications/{id}", ID: "deleteApplication", Tag: "applications", Summary: "Move an application to the trash.", Status: statusNoContent},
+	{Method: "POST", Path: "/api/applications/{id}/restore", ID: "restoreApplication", Tag: "applications", Summary: "Take an application out of the trash.",
+		Response: Application{}, ResponseHeaders: []apiParam{responseHeaderETag}},
+	{Method: "POST", Path: "/api/applications/{id}/clone", ID: "cloneApplication", Tag: "applications", Summary: "Copy an application into a new saved one.",
+		Request: cloneRequest{}, OptionalBody: true, Response: Application{}, Status: statusCreated},
+	{Method: "GET", Path: "/api/applications/{id}/history", ID: "listStatusHistory", Tag: "applications", Summary: "Status transitions, oldest first.",
+		Response: []StatusChange(nil)},
+	{Method: "GET", Path: "/api/applications/{id}/revisions", ID: "listRevisions", Tag: "revisions", Summary: "Resume and cover letter revisions, newest first.",
+		Response: []RevisionSummary(nil)},
+	{Method: "GET", Path: "/api/applications/{id}/revisions/{rev}", ID: "getRevision", Tag: "revisions", Summary: "One revision with its content.",
+		Response: Revision{}},
+	{Method: "POST", Path: "/api/applications/{id}/revisions/{rev}/restore", ID: "restoreRevision", Tag: "revisions", Summary: "Restore a revision onto the application. A stale If-Match returns version_conflict with details.current.",
+		Headers:  []apiParam{headerIfMatch},
+		Response: Application{}, ResponseHeaders: []apiParam{responseHeaderETag}},
+	{Method: "GET", Path: "/api/applications/{id}/contacts", ID: "listApplicationContacts", Tag: "contacts", Summary: "Contacts linked to the application, primary first.",
+		Response: []LinkedContact(nil)},
+	{Method: "PUT", Path: "/api/applications/{id}/contacts/{contactId}", ID: "linkContact", Tag: "contacts", Summary: "Link a contact to the application.",
+		Request: linkContactRequest{}, OptionalBody: true, Response: LinkedContact{}},
+	{Method: "DELETE", Path: "/api/applications/{id}/contacts/{contactId}", ID: "unlinkContact", Tag: "contacts", Summary: "Unlink a contact.", Status: statusNoContent},
+	{Method: "GET", Path: "/api/applications/{id}/attachments", ID: "listAttachments", Tag: "attachments", Summary: "The application's attachments.",
+		Response: []Attachment(nil)},
+	{Method: "POST", Path: "/api/applications/{id}/attachments", ID: "uploadAttachment", Tag: "attachments", Summary: "Upload one file in the multipart field \"file\".",
+		RequestTypes: []string{mediaMultipart}, Response: Attachment{}, Status: statusCreated},
+	{Method: "GET", Path: "/api/applications/{id}/attachments/{attachmentId}", ID: "downloadAttachment", Tag: "attachments", Summary: "Download an attachment.",
+		ResponseTypes: []string{mediaOctetStream}},
+	{Method: "DELETE", Path: "/api/applications/{id}/attachments/{attachmentId}", ID: "deleteAttachment", Tag: "attachments", Summary: "Delete an attachment.", Status: statusNoContent},
+	{Method: "GET", Path: "/api/applications/{id}/renders", ID: "listRenders", Tag: "pdf", Summary: "Saved renders, newest first, without their input.",
+		Response: []PDFRender(nil)},
+	{Method: "GET", Path: "/api/applications/{id}/renders/{renderId}", ID: "getRender", Tag: "pdf", Summary: "One render with its input snapshot.",
+		Response: PDFRender{}},
+	{Method: "GET", Path: "/api/applications/{id}/renders/{renderId}/download", ID: "downloadRender", Tag: "pdf", Summary: "The render's ZIP, or one PDF with doc.",
+		Query: []apiParam{queryDoc}, ResponseTypes: []string{mediaZip, mediaPDF}},
+	{Method: "GET", Path: "/api/applications/{id}/offer", ID: "getOffer", Tag: "offers", Summary: "The application's offer.",
+		Response: Offer{}},
+	{Method: "PUT", Path: "/api/applications/{id}/offer", ID: "putOffer", Tag: "offers", Summary: "Create (201) or replace (200) the offer; the negotiation history is kept.",
+		Request: Offer{}, Response: Offer{}, Status: statusCreatedOrUpdate},
+	{Method: "DELETE", Path: "/api/applications/{id}/offer", ID: "deleteOffer", Tag: "offers", Summary: "Delete the offer.", Status: statusNoContent},
+	{Method: "POST", Path: "/api/applications/{id}/offer/negotiation", ID: "appendNegotiation", Tag: "offers", Summary: "Append a negotiation event.",
+		Request: NegotiationEvent{}, Response: Offer{}, Status: statusCreated},
+
+	{Method: "GET", Path: "/api/contacts", ID: "listContacts", Tag: "contacts", Summary: "All contacts.", Response: []Contact(nil)},
+	{Method: "POST", Path: "/api/contacts", ID: "createContact", Tag: "contacts", Summary: "Create a contact.",
+		Request: Contact{}, Response: Contact{}, Status: statusCreated},
+	{Method: "GET", Path: "/api/contacts/{id}", ID: "getContact", Tag: "contacts", Summary: "Get a contact.", Response: Contact{}},
+	{Method: "PUT", Path: "/api/contacts/{id}", ID: "updateContact", Tag: "contacts", Summary: "Replace a contact.",
+		Request: Contact{}, Response: Contact{}},
+	{Method: "DELETE", Path: "/api/contacts/{id}", ID: "deleteContact", Tag: "contacts", Summary: "Delete a contact.", Status: statusNoContent},
+
+	{Method: "GET", Path: "/api/offers", ID: "listOffers", Tag: "offers", Summary: "Every offer on a live application.", Response: []Offer(nil)},
+	{Method: "GET", Path: "/api/offers/compare", ID: "compareOffers", Tag: "offers", Summary: "Annualize offers into one currency and rank them.",
+		Query: []apiParam{
+			{Name: "ids", Description: "Comma-separated offer ids.", Required: true},
+			{Name: "currency", Description: "Comparison currency; the first offer's by default."},
+			{Name: "rates", Description: "Extra exchange rates, e.g. EUR:1.08,GBP:1.27."},
+		},
+		Response: OfferComparisonResult{}},
+	{Method: "GET", Path: "/api/attachments/usage", ID: "getAttachmentUsage", Tag: "attachments", Summary: "Attachment quota usage.", Response: AttachmentUsage{}},
+	{Method: "GET", Path: "/api/reminders", ID: "listReminders", Tag: "applications", Summary: "Overdue next actions, soonest first.",
+		Query:    []apiParam{{Name: "days", Description: "Also include actions due within this many days (max 90).", Type: "integer"}},
+		Response: []Reminder(nil)},
+	{Method: "GET", Path: "/api/tags", ID: "listTags", Tag: "applications", Summary: "Tags in use, most used first.", Response: []TagCount(nil)},
+	{Method: "GET", Path: "/api/analytics", ID: "getAnalytics", Tag: "applications", Summary: "Pipeline funnel, conversions, time in stage and weekly volume.",
+		Query: []apiParam{
+			{Name: "from", Description: "YYYY-MM-DD, inclusive."},
+			{Name: "to", Description: "YYYY-MM-DD, inclusive."},
+			{Name: "tag", Description: "Comma-separated tag names; all must match."},
+		},
+		Response: Analytics{}},
+	{Method: "GET", Path: "/api/calendar.ics", ID: "exportCalendar", Tag: "calendar", Summary: "Download interviews and deadlines as iCalendar.",
+		ResponseTypes: []string{mediaCalendar}},
+	{Method: "POST", Path: "/api/calendar/feed", ID: "createCalendarFeed", Tag: "calendar", Summary: "Create or rotate the subscription feed token.",
+		Response: calendarFeed{}},
+	{Method: "DELETE", Path: "/api/calendar/feed", ID: "revokeCalendarFeed", Tag: "calendar", Summary: "Revoke the feed token.", Status: statusNoContent},
+
+	{Method: "GET", Path: "/api/export", ID: "exportAccount", Tag: "account", Summary: "ZIP of the profile and every live application.",
+		Query: []apiParam{{Name: "pdfs", Description: "1 to include rendered PDFs."}}, ResponseTypes: []string{mediaZip}},
+	{Method: "POST", Path: "/api/import", ID: "importAccount", Tag: "account", Summary: "Restore an archive from /api/export (raw ZIP or multipart field \"file\").",
+		RequestTypes: []string{mediaZip, mediaMultipart}, Response: AccountImportResult{}},
+	{Method: "POST", Path: "/api/import/legacy", ID: "importLegacy", Tag: "account", Summary: "Import a legacy JSON file or a ZIP of them (raw or multipart field \"file\").",
+		RequestTypes: []string{"application/json", mediaZip, mediaMultipart}, Response: ImportResult{}},
+	{Method: "POST", Path: "/api/account/deletion-token", ID: "createDeletionToken", Tag: "account", Summary: "Issue a one-time token for DELETE /api/account.",
+		Response: accountDeletionToken{}, Status: statusCreated},
+	{Method: "DELETE", Path: "/api/account", ID: "deleteAccount", Tag: "account", Summary: "Permanently delete everything the user owns.",
+		Headers:  []apiParam{{Name: accountDeletionTokenHeader, Description: "Token from POST /api/account/deletion-token.", Required: true}},
+		Response: AccountDeletionReceipt{}},
+}
+
+// The document is built from structs rather than maps so its keys keep a readable order.
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Security   []map[string][]string                   `json:"security"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Tags        []string                    `json:"tags"`
+	Security    *[]map[string][]string      `json:"security,omitempty"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required"`
+	Schema      *jsonSchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIMedia struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Ref         string                   `json:"$ref,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Headers     map[string]openAPIHeader `json:"headers,omitempty"`
+	Content     map[string]openAPIMedia  `json:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Description string      `json:"description"`
+	Schema      *jsonSchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*jsonSchema       `json:"schemas"`
+	Responses       map[string]*openAPIResponse  `json:"responses"`
+	SecuritySchemes map[string]map[string]string `json:"securitySchemes"`
+}
+
+// jsonSchema is the subset of JSON Schema 2020-12 the generator emits. Type is a string, or
+// [type, "null"] for values Go may encode as null.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+
+	// order is the struct field order, for generating readable client types.
+	order []string
+}
+
+// schemaGenerator turns Go types into schemas, collecting named structs as components.
+type schemaGenerator struct {
+	schemas map[string]*jsonSchema
+	types   map[string]reflect.Type
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	stringListType = reflect.TypeOf(StringList(nil))
+)
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) *jsonSchema {
+	switch t {
+	case timeType:
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &jsonSchema{}
+	case stringListType:
+		return &jsonSchema{
+			Type:        []string{"array", "null"},
+			Items:       &jsonSchema{Type: "string"},
+			Description: "A comma-separated string is also accepted.",
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := g.schemaFor(t.Elem())
+		if inner.Ref != "" {
+			return &jsonSchema{AnyOf: []*jsonSchema{inner, {Type: "null"}}}
+		}
+		if typ, ok := inner.Type.(string); ok {
+			inner.Type = []string{typ, "null"}
+		}
+		return inner
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &jsonSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &jsonSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: []string{"array", "null"}, Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: []string{"object", "null"}, AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Interface:
+		return &jsonSchema{}
+	case reflect.Struct:
+		return g.structRef(t)
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+// structRef registers t as a component schema and returns a reference to it.
+func (g *schemaGenerator) structRef(t reflect.Type) *jsonSchema {
+	name := schemaName(t)
+	ref := &jsonSchema{Ref: "#/components/schemas/" + name}
+	if seen, ok := g.types[name]; ok {
+		if seen != t {
+			panic(fmt.Sprintf("openapi: %s and %s both map to schema %s", seen, t, name))
+		}
+		return ref
+	}
+	g.types[name] = t
+
+	s := &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}, AdditionalProperties: false}
+	g.schemas[name] = s
+	g.addFields(s, t)
+	return ref
+}
+
+// addFields mirrors encoding/json: embedded structs are flattened and "-" fields skipped.
+// Fields without omitempty are required because the server always writes them; pointers stay
+// optional since they mostly mark "leave unchanged" inputs.
+func (g *schemaGenerator) addFields(s *jsonSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(s, f.Type)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schemaFor(f.Type)
+		s.order = append(s.order, name)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// schemaName is the Go type name with its first letter capitalized.
+func schemaName(t reflect.Type) string {
+	r := []rune(t.Name())
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+// pathParams returns the {name} segments of an operation path in order.
+func pathParams(path string) []string {
+	var names []string
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// buildOpenAPIDocument assembles the document from apiOperations.
+func buildOpenAPIDocument() *openAPIDocument {
+	g := &schemaGenerator{schemas: map[string]*jsonSchema{}, types: map[string]reflect.Type{}}
+	errorRef := g.schemaFor(reflect.TypeOf(apiError{}))
+
+	doc := &openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: openAPIInfo{
+			Title:   "Job Application Central API",
+			Version: "1",
+			Description: "Generated from the backend's Go types by `go run . openapi`. " +
+				"Required properties are always present in responses; request bodies may omit them. " +
+				"Errors use the Error envelope; branch on its code.",
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+		Paths:    map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas: g.schemas,
+			Responses: map[string]*openAPIResponse{
+				"Error": {
+					Description: "Error envelope.",
+					Content:     map[string]openAPIMedia{"application/json": {Schema: errorRef}},
+				},
+			},
+			SecuritySchemes: map[string]map[string]string{
+				"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+
+	for _, op := range apiOperations {
+		o := &openAPIOperation{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Tags:        []string{op.Tag},
+			Responses:   map[string]*openAPIResponse{"default": {Ref: "#/components/responses/Error"}},
+		}
+		if op.Public {
+			o.Security = &[]map[string][]string{}
+		}
+		for _, name := range pathParams(op.Path) {
+			o.Parameters = append(o.Parameters, openAPIParameter{Name: name, In: "path", Required: true, Schema: &jsonSchema{Type: "string"}})
+		}
+		for _, p := range op.Query {
+			o.Parameters = append(o.Parameters, p.parameter("query"))
+		}
+		for _, p := range op.Headers {
+			o.Parameters = append(o.Parameters, p.parameter("header"))
+		}
+
+		if op.Request != nil || len(op.RequestTypes) > 0 {
+			body := &openAPIRequestBody{Required: !op.OptionalBody, Content: map[string]openAPIMedia{}}
+			if op.Request != nil {
+				body.Content["application/json"] = openAPIMedia{Schema: g.schemaFor(reflect.TypeOf(op.Request))}
+			}
+			for _, media := range op.RequestTypes {
+				body.Content[media] = openAPIMedia{Schema: requestMediaSchema(media)}
+			}
+			o.RequestBody = body
+		}
+
+		statuses := op.Status
+		if len(statuses) == 0 {
+			statuses = []int{http.StatusOK}
+		}
+		for _, status := range statuses {
+			resp := &openAPIResponse{Description: http.StatusText(status)}
+			if status != http.StatusNoContent {
+				resp.Content = map[string]openAPIMedia{}
+				if op.Response != nil {
+					resp.Content["application/json"] = openAPIMedia{Schema: g.schemaFor(reflect.TypeOf(op.Response))}
+				}
+				for _, media := range op.ResponseTypes {
+					resp.Content[media] = openAPIMedia{Schema: responseMediaSchema(media)}
+				}
+			}
+			for _, h := range op.ResponseHeaders {
+				if resp.Headers == nil {
+					resp.Headers = map[string]openAPIHeader{}
+				}
+				resp.Headers[h.Name] = openAPIHeader{Description: h.Description, Schema: &jsonSchema{Type: "string"}}
+			}
+			o.Responses[strconv.Itoa(status)] = resp
+		}
+
+		if doc.Paths[op.Path] == nil {
+			doc.Paths[op.Path] = map[string]*openAPIOperation{}
+		}
+		doc.Paths[op.Path][strings.ToLower(op.Method)] = o
+	}
+	return doc
+}
+
+func (p apiParam) parameter(in string) openAPIParameter {
+	typ := p.Type
+	if typ == "" {
+		typ = "string"
+	}
+	return openAPIParameter{Name: p.Name, In: in, Description: p.Description, Required: p.Required, Schema: &jsonSchema{Type: typ}}
+}
+
+func requestMediaSchema(media string) *jsonSchema {
+	if media == mediaMultipart {
+		return &jsonSchema{
+			Type:       "object",
+			Properties: map[string]*jsonSchema{"file": {Type: "string", Format: "binary"}},
+			Required:   []string{"file"},
+			order:      []string{"file"},
+		}
+	}
+	return responseMediaSchema(media)
+}
+
+func responseMediaSchema(media string) *jsonSchema {
+	if media == mediaText || media == mediaCalendar {
+		return &jsonSchema{Type: "string"}
+	}
+	return &jsonSchema{Type: "string", Format: "binary"}
+}
+
+// openAPIJSON renders the document as it is committed in openapi.json.
+func openAPIJSON() ([]byte, error) {
+	out, err := json.MarshalIndent(buildOpenAPIDocument(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// routePattern is the ServeMux pattern an operation is registered under.
+func (op apiOperation) routePattern() string {
+	if op.Path == "/" {
+		return op.Method + " /{$}"
+	}
+	return op.Method + " " + op.Path
+}
+
+// sortedSchemaNames returns the component names in a stable order.
+func sortedSchemaNames(schemas map[string]*jsonSchema) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
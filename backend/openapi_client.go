@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// typeScriptClient generates a dependency-free TypeScript client (fetch-based, for Node 18+,
+// Deno, Bun or browsers) with one interface per component schema and one method per operation.
+func typeScriptClient() []byte {
+	doc := buildOpenAPIDocument()
+	var b bytes.Buffer
+	b.WriteString("// Code generated by `go run . openapi` from the backend's OpenAPI document. DO NOT EDIT.\n\n")
+
+	for _, name := range sortedSchemaNames(doc.Components.Schemas) {
+		s := doc.Components.Schemas[name]
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, prop := range s.order {
+			ps := s.Properties[prop]
+			if ps.Description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", ps.Description)
+			}
+			optional := "?"
+			if slices.Contains(s.Required, prop) {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsPropertyName(prop), optional, tsType(ps))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	b.WriteString(tsClientRuntime)
+
+	for _, op := range apiOperations {
+		o := doc.Paths[op.Path][strings.ToLower(op.Method)]
+		writeTSMethod(&b, op, o)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeTSMethod(b *bytes.Buffer, op apiOperation, o *openAPIOperation) {
+	var params, spec []string
+	path := pathParamPattern.ReplaceAllString(op.Path, "${encodeURIComponent($1)}")
+	for _, name := range pathParams(op.Path) {
+		params = append(params, name+": string")
+	}
+
+	switch {
+	case op.Request != nil && len(op.RequestTypes) == 0:
+		params = append(params, "body"+tsOptional(op.OptionalBody)+": "+tsType(o.RequestBody.Content["application/json"].Schema))
+		spec = append(spec, "json: body")
+	case len(op.RequestTypes) > 0:
+		params = append(params, "body"+tsOptional(op.OptionalBody)+": RawBody")
+		spec = append(spec, "raw: body")
+		for _, media := range op.RequestTypes {
+			if media != mediaMultipart {
+				spec = append(spec, fmt.Sprintf("rawType: %q", media))
+				break
+			}
+		}
+	}
+
+	groups := []struct {
+		key    string
+		params []apiParam
+	}{{"query", op.Query}, {"headers", op.Headers}}
+	optionsRequired := slices.ContainsFunc(append(slices.Clone(op.Query), op.Headers...), func(p apiParam) bool { return p.Required })
+	var optionFields []string
+	for _, group := range groups {
+		if len(group.params) == 0 {
+			continue
+		}
+		var fields []string
+		groupRequired := false
+		for _, p := range group.params {
+			typ := "string"
+			if p.Type == "integer" {
+				typ = "number"
+			}
+			fields = append(fields, tsPropertyName(p.Name)+tsOptional(!p.Required)+": "+typ)
+			groupRequired = groupRequired || p.Required
+		}
+		optionFields = append(optionFields, group.key+tsOptional(!groupRequired)+": { "+strings.Join(fields, "; ")+" }")
+		spec = append(spec, group.key+": options"+tsOptional(!optionsRequired)+"."+group.key)
+	}
+	if len(optionFields) > 0 {
+		params = append(params, "options"+tsOptional(!optionsRequired)+": { "+strings.Join(optionFields, "; ")+" }")
+	}
+
+	result, kind := "void", "none"
+	switch {
+	case op.Response != nil:
+		result, kind = tsType(o.Responses[fmt.Sprint(successStatus(op))].Content["application/json"].Schema), "json"
+	case slices.Contains(op.ResponseTypes, mediaText) || slices.Contains(op.ResponseTypes, mediaCalendar):
+		result, kind = "string", "text"
+	case len(op.ResponseTypes) > 0:
+		result, kind = "Blob", "blob"
+	}
+	spec = append(spec, fmt.Sprintf("response: %q", kind))
+	if slices.ContainsFunc(op.Headers, func(p apiParam) bool { return p.Name == headerGeminiKey.Name }) {
+		spec = append(spec, "ai: true")
+	}
+
+	fmt.Fprintf(b, "\n  /** %s */\n", op.Summary)
+	fmt.Fprintf(b, "  %s(%s): Promise<ApiResponse<%s>> {\n", op.ID, strings.Join(params, ", "), result)
+	fmt.Fprintf(b, "    return this.request(%q, `%s`, { %s });\n", op.Method, path, strings.Join(spec, ", "))
+	b.WriteString("  }\n")
+}
+
+func successStatus(op apiOperation) int {
+	if len(op.Status) == 0 {
+		return http.StatusOK
+	}
+	return op.Status[0]
+}
+
+func tsOptional(optional bool) string {
+	if optional {
+		return "?"
+	}
+	return ""
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// tsType renders a schema as a TypeScript type expression.
+func tsType(s *jsonSchema) string {
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	if len(s.AnyOf) > 0 {
+		parts := make([]string, len(s.AnyOf))
+		for i, alt := range s.AnyOf {
+			parts[i] = tsType(alt)
+		}
+		return strings.Join(parts, " | ")
+	}
+	switch typ := s.Type.(type) {
+	case []string:
+		parts := make([]string, len(typ))
+		for i, t := range typ {
+			parts[i] = tsSingleType(s, t)
+		}
+		return strings.Join(parts, " | ")
+	case string:
+		return tsSingleType(s, typ)
+	}
+	return "unknown"
+}
+
+func tsSingleType(s *jsonSchema, typ string) string {
+	switch typ {
+	case "string":
+		if s.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		item := tsType(s.Items)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		if extra, ok := s.AdditionalProperties.(*jsonSchema); ok {
+			return "Record<string, " + tsType(extra) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+const tsClientRuntime = `/** A request body that is sent as-is: a ZIP or JSON file, or a FormData upload. */
+export type RawBody = Blob | ArrayBuffer | Uint8Array | FormData | string;
+
+export interface ApiResponse<T> {
+  data: T;
+  status: number;
+  headers: Headers;
+}
+
+export interface ClientOptions {
+  /** Backend origin, e.g. http://localhost:8080. */
+  baseUrl: string;
+  /** Supabase access token, or a function returning a fresh one for each request. */
+  token?: string | (() => string | Promise<string>);
+  /** Sent as X-Gemini-Api-Key on AI requests. */
+  geminiApiKey?: string;
+  fetch?: typeof fetch;
+}
+
+/** A non-2xx response, carrying the backend's error envelope. Branch on code. */
+export class JobAppError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly details?: unknown;
+  readonly requestId?: string;
+
+  constructor(status: number, envelope: Partial<ApiError>) {
+    super(envelope.message || ` + "`HTTP ${status}`" + `);
+    this.name = "JobAppError";
+    this.status = status;
+    this.code = envelope.code || "unknown";
+    this.details = envelope.details;
+    this.requestId = envelope.requestId;
+  }
+}
+
+interface RequestSpec {
+  query?: Record<string, string | number | undefined>;
+  headers?: Record<string, string | undefined>;
+  json?: unknown;
+  raw?: RawBody;
+  rawType?: string;
+  response: "json" | "text" | "blob" | "none";
+  ai?: boolean;
+}
+
+export class JobAppClient {
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions) {
+    this.options = options;
+  }
+
+  private async request<T>(method: string, path: string, spec: RequestSpec): Promise<ApiResponse<T>> {
+    const url = new URL(this.options.baseUrl.replace(/\/+$/, "") + path);
+    for (const [key, value] of Object.entries(spec.query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+    const headers = new Headers();
+    for (const [key, value] of Object.entries(spec.headers ?? {})) {
+      if (value !== undefined) headers.set(key, value);
+    }
+    const token = typeof this.options.token === "function" ? await this.options.token() : this.options.token;
+    if (token) headers.set("Authorization", ` + "`Bearer ${token}`" + `);
+    if (spec.ai && this.options.geminiApiKey && !headers.has("X-Gemini-Api-Key")) {
+      headers.set("X-Gemini-Api-Key", this.options.geminiApiKey);
+    }
+
+    let body: BodyInit | undefined;
+    if (spec.json !== undefined) {
+      headers.set("Content-Type", "application/json");
+      body = JSON.stringify(spec.json);
+    } else if (spec.raw !== undefined) {
+      if (!(spec.raw instanceof FormData) && spec.rawType) headers.set("Content-Type", spec.rawType);
+      body = spec.raw as BodyInit;
+    }
+
+    const response = await (this.options.fetch ?? fetch)(url, { method, headers, body });
+    if (!response.ok) {
+      const text = await response.text();
+      let envelope: Partial<ApiError>;
+      try {
+        envelope = JSON.parse(text);
+      } catch {
+        envelope = { message: text };
+      }
+      throw new JobAppError(response.status, envelope);
+    }
+
+    let data: unknown;
+    if (spec.response === "json") data = await response.json();
+    else if (spec.response === "text") data = await response.text();
+    else if (spec.response === "blob") data = await response.blob();
+    return { data: data as T, status: response.status, headers: response.headers };
+  }
+`
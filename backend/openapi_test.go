@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestOpenAPISpecUpToDate fails when a request/response type or the operation table changed
+// without regenerating openapi.json and the TypeScript client.
+func TestOpenAPISpecUpToDate(t *testing.T) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(spec, openAPISpec) {
+		t.Error("openapi.json is out of date; run `go run . openapi`")
+	}
+	client, err := os.ReadFile("client/jobapp.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(client, typeScriptClient()) {
+		t.Error("client/jobapp.ts is out of date; run `go run . openapi`")
+	}
+}
+
+// TestOpenAPIRoutesMatchRouter checks that every registered route is documented and every
+// documented operation is registered.
+func TestOpenAPIRoutesMatchRouter(t *testing.T) {
+	registered := newAPIRouter(newDevVerifier("test")).patterns
+	documented := map[string]bool{}
+	ids := map[string]bool{}
+	for _, op := range apiOperations {
+		pattern := op.routePattern()
+		if documented[pattern] {
+			t.Errorf("%s is documented twice", pattern)
+		}
+		documented[pattern] = true
+		if ids[op.ID] {
+			t.Errorf("operation id %s is used twice", op.ID)
+		}
+		ids[op.ID] = true
+		if !slices.Contains(registered, pattern) {
+			t.Errorf("%s (%s) is documented but not registered", pattern, op.ID)
+		}
+	}
+	for _, pattern := range registered {
+		if !documented[pattern] {
+			t.Errorf("%s is registered but missing from apiOperations", pattern)
+		}
+	}
+}
+
+// TestOpenAPIResponsesMatchSchemas calls the handlers against the memory store and validates
+// each JSON response against the documented schema, so a handler that starts encoding a
+// different type (or a type that gains an untagged field) fails here.
+func TestOpenAPIResponsesMatchSchemas(t *testing.T) {
+	h := newSpecHarness(t)
+
+	app := h.do("createApplication", nil, map[string]any{
+		"jobTitle":          "Backend Engineer",
+		"company":           "Acme",
+		"applicationStatus": "applied",
+		"jobDescription":    "Go and Postgres.",
+		"deadline":          "2030-01-31",
+		"interviews":        []any{map[string]any{"scheduledAt": "2030-01-10T15:00:00Z", "format": "video"}},
+		"tags":              []any{map[string]any{"name": "remote"}},
+		"resume":            map[string]any{"name": "Ada", "jobs": []any{map[string]any{"jobTitle": "Engineer", "jobPoints": "a, b"}}},
+		"coverLetter":       map[string]any{"greeting": "Hello", "paragraphs": []string{"One."}, "closing": "Thanks"},
+	}).(map[string]any)
+	id := app["id"].(string)
+
+	app["resume"].(map[string]any)["objective"] = "Build reliable systems."
+	h.do("updateApplication", []string{id}, app, withHeader("If-Match", `"1"`), withHeader("X-Revision-Source", "manual"))
+	h.do("getApplication", []string{id}, nil)
+	h.do("listApplications", nil, nil, withQuery("limit=10"))
+	h.do("listStatusHistory", []string{id}, nil)
+	revisions := h.do("listRevisions", []string{id}, nil).([]any)
+	if len(revisions) == 0 {
+		t.Fatal("expected a revision after changing the resume")
+	}
+	rev := fmt.Sprint(revisions[0].(map[string]any)["id"])
+	h.do("getRevision", []string{id, rev}, nil)
+	h.do("restoreRevision", []string{id, rev}, nil, withHeader("If-Match", `"2"`))
+
+	contact := h.do("createContact", nil, map[string]any{"name": "Grace", "email": "grace@example.com"}).(map[string]any)
+	contactID := contact["id"].(string)
+	h.do("updateContact", []string{contactID}, map[string]any{"name": "Grace Hopper", "role": "Recruiter"})
+	h.do("getContact", []string{contactID}, nil)
+	h.do("listContacts", nil, nil)
+	h.do("linkContact", []string{id, contactID}, map[string]any{"primary": true})
+	h.do("listApplicationContacts", []string{id}, nil)
+
+	offer := h.do("putOffer", []string{id}, map[string]any{"currency": "USD", "baseSalary": 150000, "basePeriod": "year"}).(map[string]any)
+	h.do("appendNegotiation", []string{id}, map[string]any{"by": "candidate", "amount": 165000, "note": "Counter"})
+	h.do("getOffer", []string{id}, nil)
+	h.do("listOffers", nil, nil)
+	h.do("compareOffers", nil, nil, withQuery("ids="+strings.ToUpper(offer["id"].(string))))
+
+	var upload bytes.Buffer
+	mw := multipart.NewWriter(&upload)
+	part, _ := mw.CreateFormFile("file", "notes.txt")
+	part.Write([]byte("Referred by Grace.\n"))
+	mw.Close()
+	h.do("uploadAttachment", []string{id}, upload.Bytes(), withHeader("Content-Type", mw.FormDataContentType()))
+	h.do("listAttachments", []string{id}, nil)
+	h.do("getAttachmentUsage", nil, nil)
+	h.do("listRenders", []string{id}, nil)
+
+	h.do("listReminders", nil, nil, withQuery("days=90"))
+	h.do("listTags", nil, nil)
+	h.do("getAnalytics", nil, nil)
+	h.do("createCalendarFeed", nil, nil)
+	h.do("putProfile", nil, map[string]any{"name": "Ada"}, withHeader("If-None-Match", "*"))
+	h.do("getProfile", nil, nil)
+	h.do("getOpenAPI", nil, nil)
+
+	clone := h.do("cloneApplication", []string{id}, map[string]any{"company": "Globex"}).(map[string]any)
+	h.do("deleteApplication", []string{clone["id"].(string)}, nil)
+	h.do("restoreApplication", []string{clone["id"].(string)}, nil)
+
+	archive := h.do("exportAccount", nil, nil).([]byte)
+	h.do("importAccount", nil, archive, withHeader("Content-Type", "application/zip"))
+	h.do("importLegacy", nil, []byte(`{"job-title": "Analyst", "company": "Initech"}`))
+
+	deletion := h.do("createDeletionToken", nil, nil).(map[string]any)
+	h.do("deleteAccount", nil, nil, withHeader(accountDeletionTokenHeader, deletion["token"].(string)))
+
+	// Operations that need Gemini, GitHub or pdflatex aren't exercised here.
+	external := []string{"optimizeResume", "listGithubProjects", "getRender"}
+	for _, op := range apiOperations {
+		if op.Response != nil && !h.called[op.ID] && !slices.Contains(external, op.ID) {
+			t.Errorf("%s returns JSON but isn't exercised by this test", op.ID)
+		}
+	}
+}
+
+type specHarness struct {
+	t      *testing.T
+	doc    *openAPIDocument
+	router http.Handler
+	called map[string]bool
+}
+
+func newSpecHarness(t *testing.T) *specHarness {
+	t.Helper()
+	localBlobs, err := newLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevStore, prevBlobs, prevLimits := currentStore(), blobs, attachLimits
+	storeMu.Lock()
+	store = newMemoryStore()
+	storeMu.Unlock()
+	blobs = localBlobs
+	attachLimits = attachmentLimits{MaxFileBytes: 1 << 20, QuotaBytes: 10 << 20}
+	t.Cleanup(func() {
+		storeMu.Lock()
+		store = prevStore
+		storeMu.Unlock()
+		blobs, attachLimits = prevBlobs, prevLimits
+	})
+	return &specHarness{
+		t:      t,
+		doc:    buildOpenAPIDocument(),
+		router: newRouter(newDevVerifier(uuid.NewString())),
+		called: map[string]bool{},
+	}
+}
+
+func withHeader(name, value string) func(*http.Request) {
+	return func(r *http.Request) { r.Header.Set(name, value) }
+}
+
+func withQuery(query string) func(*http.Request) {
+	return func(r *http.Request) { r.URL.RawQuery = query }
+}
+
+// do calls operation id and checks the status and, for JSON responses, the body against the
+// documented schema. It returns the decoded JSON, or the raw bytes of other responses.
+func (h *specHarness) do(id string, pathValues []string, body any, opts ...func(*http.Request)) any {
+	h.t.Helper()
+	i := slices.IndexFunc(apiOperations, func(op apiOperation) bool { return op.ID == id })
+	if i < 0 {
+		h.t.Fatalf("unknown operation %s", id)
+	}
+	op := apiOperations[i]
+	h.called[id] = true
+
+	path := op.Path
+	for j, name := range pathParams(op.Path) {
+		path = strings.Replace(path, "{"+name+"}", pathValues[j], 1)
+	}
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			h.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(op.Method, path, reader)
+	req.Header.Set("Authorization", "Bearer test")
+	for _, opt := range opts {
+		opt(req)
+	}
+	rec := httptest.NewRecorder()
+	h.router.ServeHTTP(rec, req)
+
+	statuses := op.Status
+	if len(statuses) == 0 {
+		statuses = []int{http.StatusOK}
+	}
+	if !slices.Contains(statuses, rec.Code) {
+		h.t.Fatalf("%s %s: status %d, documented %v: %s", op.Method, path, rec.Code, statuses, rec.Body.String())
+	}
+	if op.Response == nil {
+		return rec.Body.Bytes()
+	}
+
+	var decoded any
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		h.t.Fatalf("%s: response is not JSON: %v", id, err)
+	}
+	schema := h.doc.Paths[op.Path][strings.ToLower(op.Method)].Responses[fmt.Sprint(rec.Code)].Content["application/json"].Schema
+	if err := h.validate(schema, decoded, "$"); err != nil {
+		h.t.Errorf("%s: response doesn't match the spec: %v", id, err)
+	}
+	return decoded
+}
+
+// validate checks v against the subset of JSON Schema that the generator emits. Objects must
+// not carry properties the schema doesn't list.
+func (h *specHarness) validate(s *jsonSchema, v any, path string) error {
+	if s.Ref != "" {
+		name := s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+		return h.validate(h.doc.Components.Schemas[name], v, path)
+	}
+	if len(s.AnyOf) > 0 {
+		var errs []string
+		for _, alt := range s.AnyOf {
+			err := h.validate(alt, v, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s matches no alternative: %s", path, strings.Join(errs, "; "))
+	}
+
+	var types []string
+	switch typ := s.Type.(type) {
+	case nil:
+		return nil
+	case string:
+		types = []string{typ}
+	case []string:
+		types = typ
+	}
+	kind := jsonKind(v)
+	if !slices.Contains(types, kind) && !(kind == "integer" && slices.Contains(types, "number")) {
+		return fmt.Errorf("%s is %s, want %s", path, kind, strings.Join(types, " or "))
+	}
+
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			if err := h.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+		extra, _ := s.AdditionalProperties.(*jsonSchema)
+		for name, value := range v {
+			prop := s.Properties[name]
+			if prop == nil {
+				prop = extra
+			}
+			if prop == nil {
+				return fmt.Errorf("%s.%s is not in the spec", path, name)
+			}
+			if err := h.validate(prop, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonKind(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
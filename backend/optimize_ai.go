@@ -34,6 +34,7 @@ func optimizeResumeWithAI(parentCtx context.Context, apiKey string, req optimize
 	- DO NOT fabricate experience, companies, technologies, metrics, or outcomes
 	- DO NOT add skills that are not explicitly present or clearly implied
 	- DO NOT add dates or employers if missing
+	- DO NOT change institutions, degrees, dates, locations or GPA in education; only reorder courses by relevance
 	- DO NOT remove existing JSON fields or change the schema
 	- DO NOT include explanations, markdown, or commentary
 	- Output MUST be valid JSON only
@@ -85,6 +86,18 @@ func optimizeResumeWithAI(parentCtx context.Context, apiKey string, req optimize
 	  "github": string,
 	  "objective": string,
 	  "relevantCourses": [string],
+	  "education": [
+		{
+		  "institution": string,
+		  "degree": string,
+		  "field": string,
+		  "startDate": string,
+		  "endDate": string,
+		  "location": string,
+		  "gpa": string,
+		  "courses": [string]
+		}
+	  ],
 	  "jobs": [
 		{
 		  "jobTitle": string,
@@ -128,6 +141,7 @@ func optimizeResumeWithAI(parentCtx context.Context, apiKey string, req optimize
 	}
 
 	prompt := systemPrompt + "\n\n" + userPrompt
+	start := time.Now()
 	result, err := client.Models.GenerateContent(
 		ctx,
 		model,
@@ -138,6 +152,7 @@ func optimizeResumeWithAI(parentCtx context.Context, apiKey string, req optimize
 			ResponseMIMEType: "application/json",
 		},
 	)
+	logGeminiCall(parentCtx, "optimize_resume", model, start, result, err)
 	if err != nil {
 		return ResumeData{}, fmt.Errorf("gemini generateContent failed: %w", err)
 	}
@@ -224,6 +239,7 @@ func optimizeCoverLetterWithAI(parentCtx context.Context, apiKey string, req opt
 	}
 
 	prompt := systemPrompt + "\n\n" + userPrompt
+	start := time.Now()
 	result, err := client.Models.GenerateContent(
 		ctx,
 		model,
@@ -234,6 +250,7 @@ func optimizeCoverLetterWithAI(parentCtx context.Context, apiKey string, req opt
 			ResponseMIMEType: "text/plain",
 		},
 	)
+	logGeminiCall(parentCtx, "optimize_cover_letter", model, start, result, err)
 	if err != nil {
 		return CoverLetter{}, fmt.Errorf("gemini generateContent failed: %w", err)
 	}
@@ -254,6 +271,21 @@ func optimizeCoverLetterWithAI(parentCtx context.Context, apiKey string, req opt
 	return normalizeOptimizedCoverLetter(optimized, req.CoverLetter), nil
 }
 
+// logGeminiCall records a Gemini request on the request's logger: which call, how long it took
+// and the token usage, never the prompt or the key.
+func logGeminiCall(ctx context.Context, call, model string, start time.Time, result *genai.GenerateContentResponse, err error) {
+	logger := loggerFrom(ctx)
+	attrs := []any{"call", call, "model", model, "durationMs", time.Since(start).Milliseconds()}
+	if err != nil {
+		logger.Warn("gemini request failed", append(attrs, "err", err)...)
+		return
+	}
+	if result != nil && result.UsageMetadata != nil {
+		attrs = append(attrs, "promptTokens", result.UsageMetadata.PromptTokenCount, "outputTokens", result.UsageMetadata.CandidatesTokenCount)
+	}
+	logger.Info("gemini request", attrs...)
+}
+
 func extractJSONObject(text string) string {
 	trimmed := strings.TrimSpace(text)
 	start := strings.Index(trimmed, "{")
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// PDFRender is a saved resume + cover letter render: what was generated for an application,
+// from which input, and the SHA-256 of each PDF.
+type PDFRender struct {
+	ID            string `json:"id"`
+	ApplicationID string `json:"applicationId"`
+	// ApplicationVersion is the saved application's version when the render was made. The
+	// rendered input may include unsaved edits; Input is the authoritative record.
+	ApplicationVersion int64     `json:"applicationVersion"`
+	ResumeFilename     string    `json:"resumeFilename"`
+	ResumeSHA256       string    `json:"resumeSha256"`
+	ResumeSize         int64     `json:"resumeSize"`
+	CoverFilename      string    `json:"coverFilename"`
+	CoverSHA256        string    `json:"coverSha256"`
+	CoverSize          int64     `json:"coverSize"`
+	CreatedAt          time.Time `json:"createdAt"`
+	// Input is the snapshot the PDFs were rendered from; it is omitted from listings.
+	Input json.RawMessage `json:"input,omitempty"`
+
+	ResumeBlobKey string `json:"-"`
+	CoverBlobKey  string `json:"-"`
+}
+
+// renderInput is the JSON snapshot stored with a render.
+type renderInput struct {
+	Application Application `json:"application"`
+	// Contact is the primary contact the cover letter fell back to, if any.
+	Contact *Contact `json:"contact,omitempty"`
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// renderBlobKey is where one PDF of a render is stored; doc is "resume" or "cover_letter".
+func renderBlobKey(userID, appID, renderID, doc string) string {
+	return "renders/" + userID + "/" + appID + "/" + renderID + "/" + doc + ".pdf"
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Revision sources record which tool produced a resume/cover letter change.
+const (
+	revisionSourceManual        = "manual"
+	revisionSourceAIResume      = "ai_resume"
+	revisionSourceAICoverLetter = "ai_cover_letter"
+	revisionSourceGithubImport  = "github_import"
+	revisionSourceRestore       = "restore"
+	revisionSourceImport        = "import"
+)
+
+var errInvalidRevisionSource = errors.New("invalid revision source")
+
+// RevisionSummary describes a stored revision without its content.
+type RevisionSummary struct {
+	ID           int64     `json:"id"`
+	Source       string    `json:"source"`
+	RestoredFrom *int64    `json:"restoredFrom,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Revision is a full snapshot of an application's resume and cover letter.
+type Revision struct {
+	RevisionSummary
+	Resume      ResumeData   `json:"resume"`
+	CoverLetter *CoverLetter `json:"coverLetter,omitempty"`
+}
+
+// revisionSourceFromRequest reads the X-Revision-Source header sent by the client when saving.
+// Edits without the header are recorded as manual.
+func revisionSourceFromRequest(r *http.Request) (string, error) {
+	source := strings.ToLower(strings.TrimSpace(r.Header.Get("X-Revision-Source")))
+	switch source {
+	case "":
+		return revisionSourceManual, nil
+	case revisionSourceManual, revisionSourceAIResume, revisionSourceAICoverLetter, revisionSourceGithubImport:
+		return source, nil
+	default:
+		return "", fmt.Errorf("%w: %q", errInvalidRevisionSource, source)
+	}
+}
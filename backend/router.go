@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	ctxRequestID ctxKey = "requestID"
+
+	requestIDHeader    = "X-Request-Id"
+	maxRequestIDLength = 128
+)
+
+// userHandler serves an authenticated request with the caller's user id and the active store.
+type userHandler func(w http.ResponseWriter, r *http.Request, s Store, userID string)
+
+// newRouter returns the server's handler: every route, tagged with request ids and logged.
+func newRouter(verifier *jwtVerifier) http.Handler {
+	return withRequestLog(newAPIRouter(verifier))
+}
+
+// newAPIRouter registers every route. Routes use method patterns, so handlers don't check
+// methods; unknown paths and methods get JSON errors from apiRouter. Each route must also be
+// described in apiOperations.
+func newAPIRouter(verifier *jwtVerifier) *apiRouter {
+	rt := &apiRouter{mux: http.NewServeMux()}
+	handle := func(pattern string, h http.HandlerFunc) {
+		rt.patterns = append(rt.patterns, pattern)
+		rt.mux.HandleFunc(pattern, h)
+	}
+	auth := func(h userHandler) http.HandlerFunc { return requireAuth(verifier, withStore(h)) }
+
+	handle("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	})
+	handle("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Welcome to the Job Application Backend!"))
+	})
+	handle("GET /api/openapi.json", handleOpenAPI)
+	handle("GET /calendar/{token}", handleCalendarFeed) // token-authenticated subscription feed
+
+	handle("GET /api/profile", auth(handleGetProfile))
+	handle("PUT /api/profile", auth(handlePutProfile))
+
+	handle("POST /api/generate-pdf", requireAuth(verifier, handleGeneratePDF))
+	handle("POST /api/preview-pdf", requireAuth(verifier, handlePreviewPDF))
+	handle("POST /api/optimize-resume", requireAuth(verifier, handleOptimizeResume))
+	handle("POST /api/optimize-coverletter", requireAuth(verifier, handleOptimizeCoverLetter))
+	handle("GET /api/github-projects", requireAuth(verifier, handleGithubProjects))
+
+	handle("GET /api/applications", auth(handleListApplications))
+	handle("POST /api/applications", auth(handleCreateApplication))
+	handle("GET /api/applications/{id}", auth(handleGetApplication))
+	handle("PUT /api/applications/{id}", auth(handleUpdateApplication))
+	handle("DELETE /api/applications/{id}", auth(handleDeleteApplication))
+	handle("POST /api/applications/{id}/restore", auth(handleApplicationRestore))
+	handle("POST /api/applications/{id}/clone", auth(handleApplicationClone))
+	handle("GET /api/applications/{id}/history", auth(handleApplicationHistory))
+	handle("GET /api/applications/{id}/revisions", auth(handleListRevisions))
+	handle("GET /api/applications/{id}/revisions/{rev}", auth(handleGetRevision))
+	handle("POST /api/applications/{id}/revisions/{rev}/restore", auth(handleRestoreRevision))
+	handle("GET /api/applications/{id}/contacts", auth(handleListApplicationContacts))
+	handle("PUT /api/applications/{id}/contacts/{contactId}", auth(handleLinkContact))
+	handle("DELETE /api/applications/{id}/contacts/{contactId}", auth(handleUnlinkContact))
+	handle("GET /api/applications/{id}/attachments", auth(handleListAttachments))
+	handle("POST /api/applications/{id}/attachments", auth(handleUploadAttachment))
+	handle("GET /api/applications/{id}/attachments/{attachmentId}", auth(handleDownloadAttachment))
+	handle("DELETE /api/applications/{id}/attachments/{attachmentId}", auth(handleDeleteAttachment))
+	handle("GET /api/applications/{id}/renders", auth(handleListRenders))
+	handle("GET /api/applications/{id}/renders/{renderId}", auth(handleGetRender))
+	handle("GET /api/applications/{id}/renders/{renderId}/download", auth(handleDownloadRender))
+	handle("GET /api/applications/{id}/offer", auth(handleGetOffer))
+	handle("PUT /api/applications/{id}/offer", auth(handlePutOffer))
+	handle("DELETE /api/applications/{id}/offer", auth(handleDeleteOffer))
+	handle("POST /api/applications/{id}/offer/negotiation", auth(handleAppendNegotiation))
+
+	handle("GET /api/contacts", auth(handleListContacts))
+	handle("POST /api/contacts", auth(handleCreateContact))
+	handle("GET /api/contacts/{id}", auth(handleGetContact))
+	handle("PUT /api/contacts/{id}", auth(handleUpdateContact))
+	handle("DELETE /api/contacts/{id}", auth(handleDeleteContact))
+
+	handle("GET /api/offers", auth(handleOffers))
+	handle("GET /api/offers/compare", auth(handleOfferCompare))
+	handle("GET /api/attachments/usage", auth(handleAttachmentUsage))
+	handle("GET /api/reminders", auth(handleReminders))
+	handle("GET /api/tags", auth(handleTags))
+	handle("GET /api/analytics", auth(handleAnalytics))
+	handle("GET /api/calendar.ics", auth(handleCalendarExport))
+	handle("POST /api/calendar/feed", auth(handleCreateCalendarFeed))
+	handle("DELETE /api/calendar/feed", auth(handleRevokeCalendarFeed))
+
+	handle("GET /api/export", auth(handleExport))
+	handle("POST /api/import", auth(handleImport))
+	handle("POST /api/import/legacy", auth(handleLegacyImport))
+	handle("POST /api/account/deletion-token", auth(handleAccountDeletionToken))
+	handle("DELETE /api/account", auth(handleDeleteAccount))
+
+	return rt
+}
+
+// withStore resolves the authenticated user and the store before calling h.
+func withStore(h userHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := userIDFromRequest(r)
+		if err != nil {
+			writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, err.Error()))
+			return
+		}
+		s := currentStore()
+		if s == nil {
+			writeError(w, r, errStoreUnavailable)
+			return
+		}
+		h(w, r, s, userID)
+	}
+}
+
+var errStoreUnavailable = newAPIError(http.StatusServiceUnavailable, codeUnavailable, "database not ready")
+
+// apiRouter answers unknown paths and methods with JSON errors instead of ServeMux's plain text.
+type apiRouter struct {
+	mux      *http.ServeMux
+	patterns []string
+}
+
+var routerMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+func (rt *apiRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := rt.mux.Handler(r); pattern != "" || r.Method == http.MethodOptions {
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+	var allowed []string
+	for _, method := range routerMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := rt.mux.Handler(probe); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+	if len(allowed) == 0 {
+		writeError(w, r, notFound("No route for "+r.URL.Path))
+		return
+	}
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, r, newAPIError(http.StatusMethodNotAllowed, codeMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path))
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxRequestID).(string)
+	return id
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Server timeouts. Writes get long enough for the slowest handlers: Gemini calls (up to two
+// minutes) and exports that render every application's PDFs. Reads allow 64 MB imports over
+// a slow connection.
+const (
+	serverReadHeaderTimeout = 10 * time.Second
+	serverReadTimeout       = 2 * time.Minute
+	serverWriteTimeout      = 5 * time.Minute
+	serverIdleTimeout       = 2 * time.Minute
+
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// newHTTPServer returns the API server. Request contexts derive from base, so cancelling it
+// aborts whatever is still running once the shutdown drain gives up.
+func newHTTPServer(addr string, h http.Handler, base context.Context) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: serverReadHeaderTimeout,
+		ReadTimeout:       serverReadTimeout,
+		WriteTimeout:      serverWriteTimeout,
+		IdleTimeout:       serverIdleTimeout,
+		BaseContext:       func(net.Listener) context.Context { return base },
+	}
+}
+
+// shutdownTimeoutFromEnv reads SHUTDOWN_TIMEOUT_SECONDS: how long in-flight requests get to
+// finish after SIGTERM or SIGINT before they are cancelled.
+func shutdownTimeoutFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv("SHUTDOWN_TIMEOUT_SECONDS"))
+	if raw == "" {
+		return defaultShutdownTimeout, nil
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid SHUTDOWN_TIMEOUT_SECONDS %q (want a whole number of seconds)", raw)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
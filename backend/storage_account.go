@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetAccountDeletionToken stores the hash of a user's deletion confirmation token, replacing any
+// previous one.
+func (s *dbStore) SetAccountDeletionToken(ctx context.Context, userID string, tokenHash []byte, expiresAt time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		insert into account_deletion_tokens (user_id, token_hash, expires_at, created_at)
+		values ($1::uuid, $2, $3, now())
+		on conflict (user_id) do update
+		set token_hash = excluded.token_hash, expires_at = excluded.expires_at, created_at = excluded.created_at
+	`, userID, tokenHash, expiresAt)
+	return err
+}
+
+// DeleteAccount removes all of a user's data in one transaction, provided tokenHash matches an
+// unexpired deletion token. It returns the blob keys of the removed attachments and renders;
+// the caller deletes the blobs once the transaction has committed.
+func (s *dbStore) DeleteAccount(ctx context.Context, userID string, tokenHash []byte) (AccountDeletionReceipt, []string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Consuming the token first makes concurrent deletes with the same token wait, then fail.
+	ct, err := tx.Exec(ctx, `
+		delete from account_deletion_tokens
+		where user_id = $1::uuid and token_hash = $2 and expires_at > now()
+	`, userID, tokenHash)
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	if ct.RowsAffected() == 0 {
+		return AccountDeletionReceipt{}, nil, errInvalidDeletionToken
+	}
+
+	// Rows removed by cascades are counted up front.
+	receipt := AccountDeletionReceipt{UserID: userID}
+	err = tx.QueryRow(ctx, `
+		select (select count(*) from attachments where user_id = $1::uuid),
+		       (select count(*) from pdf_renders where user_id = $1::uuid),
+		       (select count(*) from application_status_history where user_id = $1::uuid),
+		       (select count(*) from application_revisions where user_id = $1::uuid),
+		       (select count(*) from application_revisions where user_id = $1::uuid and source in ('ai_resume', 'ai_cover_letter'))
+	`, userID).Scan(&receipt.Attachments, &receipt.Renders, &receipt.StatusChanges, &receipt.Revisions, &receipt.AIRevisions)
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		with attachments_gone as (
+			delete from attachments where user_id = $1::uuid returning blob_key
+		), renders_gone as (
+			delete from pdf_renders where user_id = $1::uuid returning resume_blob_key, cover_blob_key
+		)
+		select blob_key from attachments_gone
+		union all select resume_blob_key from renders_gone
+		union all select cover_blob_key from renders_gone
+	`, userID)
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	blobKeys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+
+	for _, d := range []struct {
+		sql string
+		n   *int64
+	}{
+		{`delete from offers where user_id = $1::uuid`, &receipt.Offers},
+		{`delete from applications where user_id = $1::uuid`, &receipt.Applications},
+		{`delete from contacts where user_id = $1::uuid`, &receipt.Contacts},
+		{`delete from tags where user_id = $1::uuid`, &receipt.Tags},
+	} {
+		ct, err := tx.Exec(ctx, d.sql, userID)
+		if err != nil {
+			return AccountDeletionReceipt{}, nil, err
+		}
+		*d.n = ct.RowsAffected()
+	}
+	ct, err = tx.Exec(ctx, `delete from profiles where user_id = $1::uuid`, userID)
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	receipt.Profile = ct.RowsAffected() > 0
+	ct, err = tx.Exec(ctx, `delete from calendar_feed_tokens where user_id = $1::uuid`, userID)
+	if err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	receipt.CalendarFeed = ct.RowsAffected() > 0
+
+	if err := tx.QueryRow(ctx, `select now()`).Scan(&receipt.DeletedAt); err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return AccountDeletionReceipt{}, nil, err
+	}
+	return receipt, blobKeys, nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// analyticsCTE selects the applications an analytics query covers ($1 user, $2 from, $3 to,
+// $4 tags) and, per application, the furthest pipeline stage it reached (0 = saved ...
+// 5 = accepted, -1 if it never entered the pipeline) and whether it was rejected straight
+// after applying.
+const analyticsCTE = `
+	with apps as (
+		select a.id, a.application_status, a.company, a.job_title, a.created_at, a.applied_on
+		from applications a
+		where a.user_id = $1::uuid and a.deleted_at is null
+		  and ($2::date is null or a.created_at >= $2::date)
+		  and ($3::date is null or a.created_at < $3::date + 1)
+		  and (cardinality($4::text[]) = 0 or a.id in (
+			select at.application_id
+			from application_tags at
+			join tags t on t.id = at.tag_id
+			where at.user_id = $1::uuid and lower(t.name) = any($4::text[])
+			group by at.application_id
+			having count(*) = cardinality($4::text[])
+		  ))
+	), stages as (
+		select apps.id,
+		       max(case s when 'saved' then 0 when 'applied' then 1 when 'screening' then 2
+		                  when 'interview' then 3 when 'offer' then 4 when 'accepted' then 5 else -1 end) as max_stage,
+		       coalesce(bool_or(h.to_status = 'rejected' and h.from_status = 'applied'), false) as rejected_after_applying
+		from apps
+		left join application_status_history h on h.application_id = apps.id
+		cross join lateral (values (apps.application_status), (h.to_status)) v(s)
+		group by apps.id
+	)`
+
+// Analytics computes pipeline statistics in SQL inside one read-only snapshot.
+func (s *dbStore) Analytics(ctx context.Context, userID string, q AnalyticsQuery) (Analytics, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return Analytics{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var from, to any
+	if q.From != "" {
+		from = q.From
+	}
+	if q.To != "" {
+		to = q.To
+	}
+	tags := q.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	args := []any{userID, from, to, tags}
+	a := newAnalytics(q)
+
+	// Funnel: how many applications reached each stage, and how many got a response.
+	reached := make([]int, len(funnelStages))
+	var responded int
+	err = tx.QueryRow(ctx, analyticsCTE+`
+		select count(*),
+		       count(*) filter (where max_stage >= 0), count(*) filter (where max_stage >= 1),
+		       count(*) filter (where max_stage >= 2), count(*) filter (where max_stage >= 3),
+		       count(*) filter (where max_stage >= 4), count(*) filter (where max_stage >= 5),
+		       count(*) filter (where max_stage >= 1 and (max_stage >= 2 or rejected_after_applying))
+		from stages
+	`, args...).Scan(&a.Applications, &reached[0], &reached[1], &reached[2], &reached[3], &reached[4], &reached[5], &responded)
+	if err != nil {
+		return Analytics{}, err
+	}
+	a.setFunnel(reached, responded)
+
+	rows, err := tx.Query(ctx, analyticsCTE+`
+		select application_status, count(*) from apps group by application_status
+	`, args...)
+	if err != nil {
+		return Analytics{}, err
+	}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			rows.Close()
+			return Analytics{}, err
+		}
+		a.StatusCounts[status] = n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return Analytics{}, err
+	}
+
+	// Median time in stage, from completed stays in the status history.
+	rows, err = tx.Query(ctx, analyticsCTE+`, stays as (
+			select h.to_status as status,
+			       extract(epoch from lead(h.changed_at) over (partition by h.application_id order by h.changed_at, h.id) - h.changed_at) as seconds
+			from application_status_history h
+			join apps on apps.id = h.application_id
+		)
+		select status, percentile_cont(0.5) within group (order by seconds), count(*)
+		from stays
+		where seconds is not null
+		group by status
+		order by case status when 'saved' then 0 when 'applied' then 1 when 'screening' then 2 when 'interview' then 3
+		                     when 'offer' then 4 when 'accepted' then 5 when 'rejected' then 6 else 7 end
+	`, args...)
+	if err != nil {
+		return Analytics{}, err
+	}
+	for rows.Next() {
+		var d StageDuration
+		var seconds float64
+		if err := rows.Scan(&d.Status, &seconds, &d.Samples); err != nil {
+			rows.Close()
+			return Analytics{}, err
+		}
+		d.MedianDays = secondsToDays(seconds)
+		a.TimeInStage = append(a.TimeInStage, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return Analytics{}, err
+	}
+
+	// Weekly volume, with empty weeks filled in. With no applications and no from date there is
+	// no lower bound, and so no series.
+	rows, err = tx.Query(ctx, analyticsCTE+`, bounds as (
+			select date_trunc('week', coalesce($2::date::timestamptz, min(created_at))) as lo,
+			       date_trunc('week', coalesce($3::date::timestamptz, now())) as hi
+			from apps
+		)
+		select to_char(w.week, 'YYYY-MM-DD'),
+		       (select count(*) from apps where date_trunc('week', apps.created_at) = w.week),
+		       (select count(*) from apps where date_trunc('week', apps.applied_on::timestamptz) = w.week)
+		from bounds
+		cross join lateral generate_series(greatest(bounds.lo, bounds.hi - interval '1 week' * ($5::int - 1)), bounds.hi, interval '1 week') w(week)
+		where bounds.lo is not null
+		order by w.week
+	`, append(args, maxAnalyticsWeeks)...)
+	if err != nil {
+		return Analytics{}, err
+	}
+	for rows.Next() {
+		var wv WeeklyVolume
+		if err := rows.Scan(&wv.WeekStart, &wv.Created, &wv.Applied); err != nil {
+			rows.Close()
+			return Analytics{}, err
+		}
+		a.WeeklyVolume = append(a.WeeklyVolume, wv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return Analytics{}, err
+	}
+
+	// Conversion to interview by company and by job title. Names compare bytewise (collate "C"),
+	// as in computeAnalytics, so the displayed spelling and tie order don't depend on the locale.
+	for _, g := range []struct {
+		column string
+		into   *[]GroupConversion
+	}{{"company", &a.ByCompany}, {"job_title", &a.ByRole}} {
+		rows, err := tx.Query(ctx, analyticsCTE+`
+			select min(btrim(apps.`+g.column+`) collate "C"), count(*),
+			       count(*) filter (where max_stage >= 1), count(*) filter (where max_stage >= 3),
+			       count(*) filter (where max_stage >= 4)
+			from apps
+			join stages using (id)
+			where btrim(apps.`+g.column+`) <> ''
+			group by lower(btrim(apps.`+g.column+`))
+			order by count(*) desc, lower(btrim(apps.`+g.column+`)) collate "C"
+			limit $5
+		`, append(args, maxAnalyticsGroups)...)
+		if err != nil {
+			return Analytics{}, err
+		}
+		for rows.Next() {
+			var name string
+			var total, applied, interviews, offers int
+			if err := rows.Scan(&name, &total, &applied, &interviews, &offers); err != nil {
+				rows.Close()
+				return Analytics{}, err
+			}
+			*g.into = append(*g.into, newGroupConversion(name, total, applied, interviews, offers))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return Analytics{}, err
+		}
+	}
+
+	return a, nil
+}
@@ -6,45 +6,137 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 var errNotFound = errors.New("not found")
 
-func (s *dbStore) ListApplicationSummaries(ctx context.Context, userID string) ([]ApplicationSummary, error) {
-	rows, err := s.pool.Query(ctx, `
-		select id::text, job_title, company, application_status
+// statusChangedAtSQL selects when application a last changed status, falling back to its creation.
+const statusChangedAtSQL = `coalesce((select max(h.changed_at) from application_status_history h where h.application_id = a.id), a.created_at)`
+
+func (s *dbStore) ListApplicationSummaries(ctx context.Context, userID string, q ApplicationListQuery) (ApplicationPage, error) {
+	args := []any{userID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"user_id = $1::uuid", "deleted_at is null"}
+	if q.Trash {
+		where[1] = "deleted_at is not null"
+	}
+	if len(q.Statuses) > 0 {
+		where = append(where, "application_status = any("+arg(q.Statuses)+"::text[])")
+	}
+	if q.Company != "" {
+		where = append(where, "lower(company) = lower("+arg(q.Company)+")")
+	}
+	if len(q.Tags) > 0 {
+		where = append(where, fmt.Sprintf(`id in (
+			select at.application_id
+			from application_tags at
+			join tags t on t.id = at.tag_id
+			where at.user_id = $1::uuid and lower(t.name) = any(%s::text[])
+			group by at.application_id
+			having count(*) = %d
+		)`, arg(q.Tags), len(q.Tags)))
+	}
+	if q.Search != "" {
+		// Matches the trigram index on the same expression (see migrations/005).
+		where = append(where, "(job_title || ' ' || company || ' ' || job_description) ilike "+arg("%"+escapeLike(q.Search)+"%"))
+	}
+
+	sortCol := "updated_at"
+	switch q.Sort {
+	case sortCreated:
+		sortCol = "created_at"
+	case sortCompany:
+		sortCol = "lower(company)"
+	}
+	dir, cmp := "asc", ">"
+	if q.Desc {
+		dir, cmp = "desc", "<"
+	}
+	if q.After != nil {
+		var key any = q.After.Key
+		cast := ""
+		if q.Sort != sortCompany {
+			t, err := parseCursorTime(q.After.Key)
+			if err != nil {
+				return ApplicationPage{}, err
+			}
+			key, cast = t, "::timestamptz"
+		}
+		if _, err := uuid.Parse(q.After.ID); err != nil {
+			return ApplicationPage{}, fmt.Errorf("%w: malformed cursor", errInvalidListQuery)
+		}
+		where = append(where, fmt.Sprintf("(%s, id) %s (%s%s, %s::uuid)", sortCol, cmp, arg(key), cast, arg(q.After.ID)))
+	}
+
+	sql := fmt.Sprintf(`
+		select id::text, job_title, company, application_status, created_at, updated_at, deleted_at, coalesce(parent_id::text, '')
 		from applications
-		where user_id = $1::uuid
-		order by updated_at desc
-	`, userID)
+		where %s
+		order by %s %s, id %s
+		limit %s
+	`, strings.Join(where, " and "), sortCol, dir, dir, arg(q.Limit+1))
+
+	rows, err := s.pool.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return ApplicationPage{}, err
 	}
 	defer rows.Close()
 
 	var out []ApplicationSummary
 	for rows.Next() {
 		var a ApplicationSummary
-		if err := rows.Scan(&a.ID, &a.JobTitle, &a.Company, &a.ApplicationStatus); err != nil {
-			return nil, err
+		if err := rows.Scan(&a.ID, &a.JobTitle, &a.Company, &a.ApplicationStatus, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt, &a.ParentID); err != nil {
+			return ApplicationPage{}, err
 		}
 		out = append(out, a)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return ApplicationPage{}, err
 	}
-	if out == nil {
-		out = []ApplicationSummary{}
+
+	page := q.page(out)
+	ids := make([]string, len(page.Items))
+	for i, a := range page.Items {
+		ids[i] = a.ID
+	}
+	tags, err := loadApplicationTags(ctx, s.pool, userID, ids)
+	if err != nil {
+		return ApplicationPage{}, err
 	}
-	return out, nil
+	for i := range page.Items {
+		page.Items[i].Tags = tags[page.Items[i].ID]
+	}
+	return page, nil
 }
 
 func (s *dbStore) CreateApplication(ctx context.Context, userID string, app Application) (Application, error) {
 	id := uuid.New()
 	app.ID = id.String()
 
+	status, err := normalizeApplicationStatus(app.ApplicationStatus)
+	if err != nil {
+		return Application{}, err
+	}
+	app.ApplicationStatus = status
+	now := time.Now()
+	if err := normalizeSchedule(&app); err != nil {
+		return Application{}, err
+	}
+	defaultAppliedOn(&app, statusSaved, now)
+	if app.Tags != nil {
+		if app.Tags, err = normalizeTags(app.Tags); err != nil {
+			return Application{}, err
+		}
+	}
+
 	resumeBytes, err := json.Marshal(app.Resume)
 	if err != nil {
 		return Application{}, err
@@ -56,32 +148,161 @@ func (s *dbStore) CreateApplication(ctx context.Context, userID string, app Appl
 			return Application{}, err
 		}
 	}
+	interviewBytes, err := json.Marshal(app.Interviews)
+	if err != nil {
+		return Application{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Application{}, err
+	}
+	defer tx.Rollback(ctx)
 
-	_, err = s.pool.Exec(ctx, `
-		insert into applications (id, user_id, job_title, company, application_status, job_description, resume, cover_letter, created_at, updated_at)
-		values ($1::uuid, $2::uuid, $3, $4, $5, $6, $7::jsonb, $8::jsonb, now(), now())
+	app.Version = 1
+	_, err = tx.Exec(ctx, `
+		insert into applications (id, user_id, job_title, company, application_status, job_description, resume, cover_letter,
+		                          deadline, applied_on, interviews, parent_id, version, created_at, updated_at)
+		values ($1::uuid, $2::uuid, $3, $4, $5, $6, $7::jsonb, $8::jsonb, nullif($9, '')::date, nullif($10, '')::date, $11::jsonb,
+		        nullif($12, '')::uuid, 1, now(), now())
 	`,
 		id, userID, app.JobTitle, app.Company, app.ApplicationStatus, app.JobDescription, string(resumeBytes), nullableJSONB(coverBytes),
+		app.Deadline, app.AppliedOn, string(interviewBytes), app.ParentID,
 	)
 	if err != nil {
 		return Application{}, err
 	}
+	if err := insertStatusChange(ctx, tx, userID, app.ID, nil, app.ApplicationStatus); err != nil {
+		return Application{}, err
+	}
+	if err := insertRevision(ctx, tx, userID, app.ID, revisionSourceManual, nil, resumeBytes, coverBytes); err != nil {
+		return Application{}, err
+	}
+	if app.Tags, err = setApplicationTags(ctx, tx, userID, app.ID, app.Tags); err != nil {
+		return Application{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Application{}, err
+	}
 
+	applyNextAction(&app, now, now)
 	return app, nil
 }
 
+func (s *dbStore) ImportApplication(ctx context.Context, userID string, app Application) (Application, bool, error) {
+	status, err := normalizeApplicationStatus(app.ApplicationStatus)
+	if err != nil {
+		return Application{}, false, err
+	}
+	app.ApplicationStatus = status
+	if _, err := uuid.Parse(app.ID); err != nil {
+		app.ID = uuid.New().String()
+	}
+	app.ParentID = ""
+	now := time.Now()
+	if err := normalizeSchedule(&app); err != nil {
+		return Application{}, false, err
+	}
+	defaultAppliedOn(&app, statusSaved, now)
+	if app.Tags, err = normalizeTags(app.Tags); err != nil {
+		return Application{}, false, err
+	}
+
+	resumeBytes, err := json.Marshal(app.Resume)
+	if err != nil {
+		return Application{}, false, err
+	}
+	var coverBytes []byte
+	if app.CoverLetter != nil {
+		coverBytes, err = json.Marshal(app.CoverLetter)
+		if err != nil {
+			return Application{}, false, err
+		}
+	}
+	interviewBytes, err := json.Marshal(app.Interviews)
+	if err != nil {
+		return Application{}, false, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Application{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	app.Version = 1
+	insert := func() (int64, error) {
+		ct, err := tx.Exec(ctx, `
+			insert into applications (id, user_id, job_title, company, application_status, job_description, resume, cover_letter,
+			                          deadline, applied_on, interviews, version, created_at, updated_at)
+			values ($1::uuid, $2::uuid, $3, $4, $5, $6, $7::jsonb, $8::jsonb, nullif($9, '')::date, nullif($10, '')::date, $11::jsonb, 1, now(), now())
+			on conflict (id) do nothing
+		`, app.ID, userID, app.JobTitle, app.Company, app.ApplicationStatus, app.JobDescription, string(resumeBytes), nullableJSONB(coverBytes),
+			app.Deadline, app.AppliedOn, string(interviewBytes))
+		if err != nil {
+			return 0, err
+		}
+		return ct.RowsAffected(), nil
+	}
+
+	n, err := insert()
+	if err != nil {
+		return Application{}, false, err
+	}
+	if n == 0 {
+		var owner string
+		if err := tx.QueryRow(ctx, `select user_id::text from applications where id = $1::uuid`, app.ID).Scan(&owner); err != nil {
+			return Application{}, false, err
+		}
+		if owner == userID {
+			return app, false, nil
+		}
+		app.ID = importFallbackID(userID, app.ID)
+		if n, err = insert(); err != nil {
+			return Application{}, false, err
+		}
+		if n == 0 {
+			// An earlier import already stored it under the fallback id.
+			return app, false, nil
+		}
+	}
+
+	if err := insertStatusChange(ctx, tx, userID, app.ID, nil, app.ApplicationStatus); err != nil {
+		return Application{}, false, err
+	}
+	if err := insertRevision(ctx, tx, userID, app.ID, revisionSourceImport, nil, resumeBytes, coverBytes); err != nil {
+		return Application{}, false, err
+	}
+	if app.Tags, err = setApplicationTags(ctx, tx, userID, app.ID, app.Tags); err != nil {
+		return Application{}, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Application{}, false, err
+	}
+	applyNextAction(&app, now, now)
+	return app, true, nil
+}
+
 func (s *dbStore) GetApplication(ctx context.Context, userID, id string) (Application, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return Application{}, errNotFound
+	}
 	var app Application
 	app.ID = id
 
 	var resumeRaw []byte
 	var coverRaw []byte
 	var coverIsNull bool
+	var interviewsRaw []byte
+	var statusChangedAt time.Time
 	err := s.pool.QueryRow(ctx, `
-		select job_title, company, application_status, job_description, resume, cover_letter
-		from applications
-		where user_id = $1::uuid and id = $2::uuid
-	`, userID, id).Scan(&app.JobTitle, &app.Company, &app.ApplicationStatus, &app.JobDescription, &resumeRaw, &coverRaw)
+		select job_title, company, application_status, job_description, resume, cover_letter, version,
+		       coalesce(to_char(deadline, 'YYYY-MM-DD'), ''), coalesce(to_char(applied_on, 'YYYY-MM-DD'), ''), interviews,
+		       coalesce(parent_id::text, ''), `+statusChangedAtSQL+`
+		from applications a
+		where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, id).Scan(&app.JobTitle, &app.Company, &app.ApplicationStatus, &app.JobDescription, &resumeRaw, &coverRaw, &app.Version,
+		&app.Deadline, &app.AppliedOn, &interviewsRaw, &app.ParentID, &statusChangedAt)
 	if err != nil {
 		return Application{}, err
 	}
@@ -89,6 +310,9 @@ func (s *dbStore) GetApplication(ctx context.Context, userID, id string) (Applic
 	if err := json.Unmarshal(resumeRaw, &app.Resume); err != nil {
 		return Application{}, err
 	}
+	if err := json.Unmarshal(interviewsRaw, &app.Interviews); err != nil {
+		return Application{}, err
+	}
 
 	// coverRaw will be nil if NULL; Scan into []byte yields nil.
 	coverIsNull = len(coverRaw) == 0
@@ -100,13 +324,42 @@ func (s *dbStore) GetApplication(ctx context.Context, userID, id string) (Applic
 		app.CoverLetter = &cl
 	}
 
+	tags, err := loadApplicationTags(ctx, s.pool, userID, []string{id})
+	if err != nil {
+		return Application{}, err
+	}
+	app.Tags = tags[id]
+
+	applyNextAction(&app, statusChangedAt, time.Now())
 	return app, nil
 }
 
-func (s *dbStore) UpdateApplication(ctx context.Context, userID string, app Application) (Application, error) {
+// UpdateApplication saves an application, recording a status transition and, when the resume or
+// cover letter changed, a new revision attributed to source. app.Version must match the stored
+// version (optimistic concurrency); otherwise errVersionConflict is returned.
+func (s *dbStore) UpdateApplication(ctx context.Context, userID string, app Application, source string) (Application, error) {
 	if strings.TrimSpace(app.ID) == "" {
 		return Application{}, fmt.Errorf("id required")
 	}
+	if _, err := uuid.Parse(app.ID); err != nil {
+		return Application{}, errNotFound
+	}
+
+	// A blank status keeps the current one, which is only known once the row is locked.
+	status, err := normalizeUpdateStatus(app.ApplicationStatus)
+	if err != nil {
+		return Application{}, err
+	}
+	app.ApplicationStatus = status
+	now := time.Now()
+	if err := normalizeSchedule(&app); err != nil {
+		return Application{}, err
+	}
+	if app.Tags != nil {
+		if app.Tags, err = normalizeTags(app.Tags); err != nil {
+			return Application{}, err
+		}
+	}
 
 	resumeBytes, err := json.Marshal(app.Resume)
 	if err != nil {
@@ -119,8 +372,47 @@ func (s *dbStore) UpdateApplication(ctx context.Context, userID string, app Appl
 			return Application{}, err
 		}
 	}
+	interviewBytes, err := json.Marshal(app.Interviews)
+	if err != nil {
+		return Application{}, err
+	}
 
-	ct, err := s.pool.Exec(ctx, `
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Application{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	var currentVersion int64
+	var contentUnchanged bool
+	var statusChangedAt time.Time
+	err = tx.QueryRow(ctx, `
+		select application_status, version,
+		       resume = $3::jsonb and cover_letter is not distinct from $4::jsonb,
+		       coalesce(parent_id::text, ''), `+statusChangedAtSQL+`
+		from applications a
+		where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+		for update
+	`, userID, app.ID, string(resumeBytes), nullableJSONB(coverBytes)).Scan(&current, &currentVersion, &contentUnchanged, &app.ParentID, &statusChangedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Application{}, errNotFound
+		}
+		return Application{}, err
+	}
+	if currentVersion != app.Version {
+		return Application{}, errVersionConflict
+	}
+	if app.ApplicationStatus == "" {
+		app.ApplicationStatus = current
+	}
+	if err := checkStatusTransition(current, app.ApplicationStatus); err != nil {
+		return Application{}, err
+	}
+	defaultAppliedOn(&app, current, now)
+
+	_, err = tx.Exec(ctx, `
 		update applications
 		set job_title = $3,
 		    company = $4,
@@ -128,21 +420,57 @@ func (s *dbStore) UpdateApplication(ctx context.Context, userID string, app Appl
 		    job_description = $6,
 		    resume = $7::jsonb,
 		    cover_letter = $8::jsonb,
+		    deadline = nullif($9, '')::date,
+		    applied_on = nullif($10, '')::date,
+		    interviews = $11::jsonb,
+		    version = version + 1,
 		    updated_at = now()
 		where user_id = $1::uuid and id = $2::uuid
-	`, userID, app.ID, app.JobTitle, app.Company, app.ApplicationStatus, app.JobDescription, string(resumeBytes), nullableJSONB(coverBytes))
+	`, userID, app.ID, app.JobTitle, app.Company, app.ApplicationStatus, app.JobDescription, string(resumeBytes), nullableJSONB(coverBytes),
+		app.Deadline, app.AppliedOn, string(interviewBytes))
 	if err != nil {
 		return Application{}, err
 	}
-	if ct.RowsAffected() == 0 {
-		return Application{}, errNotFound
+	app.Version = currentVersion + 1
+	if current != app.ApplicationStatus {
+		if err := insertStatusChange(ctx, tx, userID, app.ID, &current, app.ApplicationStatus); err != nil {
+			return Application{}, err
+		}
+		statusChangedAt = now
+	}
+	if !contentUnchanged {
+		if err := insertRevision(ctx, tx, userID, app.ID, source, nil, resumeBytes, coverBytes); err != nil {
+			return Application{}, err
+		}
+	}
+	if app.Tags != nil {
+		app.Tags, err = setApplicationTags(ctx, tx, userID, app.ID, app.Tags)
+	} else {
+		var tags map[string][]Tag
+		tags, err = loadApplicationTags(ctx, tx, userID, []string{app.ID})
+		app.Tags = tags[app.ID]
+	}
+	if err != nil {
+		return Application{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Application{}, err
 	}
 
+	applyNextAction(&app, statusChangedAt, now)
 	return app, nil
 }
 
+// DeleteApplication moves an application to the trash. It stays restorable until PurgeTrash removes it.
 func (s *dbStore) DeleteApplication(ctx context.Context, userID, id string) error {
-	ct, err := s.pool.Exec(ctx, `delete from applications where user_id = $1::uuid and id = $2::uuid`, userID, id)
+	if _, err := uuid.Parse(id); err != nil {
+		return errNotFound
+	}
+	ct, err := s.pool.Exec(ctx, `
+		update applications
+		set deleted_at = now()
+		where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, id)
 	if err != nil {
 		return err
 	}
@@ -152,6 +480,67 @@ func (s *dbStore) DeleteApplication(ctx context.Context, userID, id string) erro
 	return nil
 }
 
+// RestoreApplication takes an application back out of the trash.
+func (s *dbStore) RestoreApplication(ctx context.Context, userID, id string) (Application, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return Application{}, errNotFound
+	}
+	ct, err := s.pool.Exec(ctx, `
+		update applications
+		set deleted_at = null
+		where user_id = $1::uuid and id = $2::uuid and deleted_at is not null
+	`, userID, id)
+	if err != nil {
+		return Application{}, err
+	}
+	if ct.RowsAffected() == 0 {
+		return Application{}, errNotFound
+	}
+	return s.GetApplication(ctx, userID, id)
+}
+
+// PurgeTrash permanently deletes applications (of every user) trashed before cutoff.
+// History, revisions, attachments and PDF renders go with them; the blob keys of attachments
+// and renders are returned so the caller can delete the bytes.
+func (s *dbStore) PurgeTrash(ctx context.Context, cutoff time.Time) (int64, []string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		with attachments_gone as (
+			delete from attachments
+			where application_id in (select id from applications where deleted_at < $1)
+			returning blob_key
+		), renders_gone as (
+			delete from pdf_renders
+			where application_id in (select id from applications where deleted_at < $1)
+			returning resume_blob_key, cover_blob_key
+		)
+		select blob_key from attachments_gone
+		union all select resume_blob_key from renders_gone
+		union all select cover_blob_key from renders_gone
+	`, cutoff)
+	if err != nil {
+		return 0, nil, err
+	}
+	blobKeys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ct, err := tx.Exec(ctx, `delete from applications where deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return ct.RowsAffected(), blobKeys, nil
+}
+
 func nullableJSONB(raw []byte) any {
 	if len(raw) == 0 {
 		return nil
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const attachmentColumns = `id::text, application_id::text, filename, content_type, size_bytes, sha256, blob_key, created_at`
+
+func scanAttachment(row pgx.Row) (Attachment, error) {
+	var a Attachment
+	err := row.Scan(&a.ID, &a.ApplicationID, &a.Filename, &a.ContentType, &a.Size, &a.SHA256, &a.BlobKey, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Attachment{}, errNotFound
+	}
+	return a, err
+}
+
+func (s *dbStore) ListAttachments(ctx context.Context, userID, appID string) ([]Attachment, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return nil, errNotFound
+	}
+	var exists bool
+	err := s.pool.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, appID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		select `+attachmentColumns+`
+		from attachments
+		where user_id = $1::uuid and application_id = $2::uuid
+		order by created_at, id
+	`, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// CreateAttachment records an uploaded attachment whose bytes are already in the blob store.
+// It fails with errAttachmentQuota if the user's attachments would exceed quotaBytes in total.
+func (s *dbStore) CreateAttachment(ctx context.Context, userID string, a Attachment, quotaBytes int64) (Attachment, error) {
+	if _, err := uuid.Parse(a.ApplicationID); err != nil {
+		return Attachment{}, errNotFound
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize uploads per user so concurrent requests can't overshoot the quota together.
+	if _, err := tx.Exec(ctx, `select pg_advisory_xact_lock(hashtextextended('attachments:' || $1, 0))`, userID); err != nil {
+		return Attachment{}, err
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null for share
+	`, userID, a.ApplicationID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Attachment{}, errNotFound
+	} else if err != nil {
+		return Attachment{}, err
+	}
+
+	var used int64
+	if err := tx.QueryRow(ctx, `select coalesce(sum(size_bytes), 0) from attachments where user_id = $1::uuid`, userID).Scan(&used); err != nil {
+		return Attachment{}, err
+	}
+	if used+a.Size > quotaBytes {
+		return Attachment{}, errAttachmentQuota
+	}
+
+	saved, err := scanAttachment(tx.QueryRow(ctx, `
+		insert into attachments (id, application_id, user_id, filename, content_type, size_bytes, sha256, blob_key)
+		values ($1::uuid, $2::uuid, $3::uuid, $4, $5, $6, $7, $8)
+		returning `+attachmentColumns,
+		a.ID, a.ApplicationID, userID, a.Filename, a.ContentType, a.Size, a.SHA256, a.BlobKey))
+	if err != nil {
+		return Attachment{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Attachment{}, err
+	}
+	return saved, nil
+}
+
+func (s *dbStore) GetAttachment(ctx context.Context, userID, appID, id string) (Attachment, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Attachment{}, errNotFound
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return Attachment{}, errNotFound
+	}
+	return scanAttachment(s.pool.QueryRow(ctx, `
+		select `+attachmentColumns+`
+		from attachments t
+		where t.user_id = $1::uuid and t.application_id = $2::uuid and t.id = $3::uuid
+		  and exists (select 1 from applications a where a.id = t.application_id and a.deleted_at is null)
+	`, userID, appID, id))
+}
+
+// DeleteAttachment removes an attachment's record and returns it so the caller can delete the blob.
+func (s *dbStore) DeleteAttachment(ctx context.Context, userID, appID, id string) (Attachment, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Attachment{}, errNotFound
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return Attachment{}, errNotFound
+	}
+	return scanAttachment(s.pool.QueryRow(ctx, `
+		delete from attachments t
+		where t.user_id = $1::uuid and t.application_id = $2::uuid and t.id = $3::uuid
+		  and exists (select 1 from applications a where a.id = t.application_id and a.deleted_at is null)
+		returning `+attachmentColumns,
+		userID, appID, id))
+}
+
+func (s *dbStore) AttachmentUsage(ctx context.Context, userID string) (int64, error) {
+	var used int64
+	err := s.pool.QueryRow(ctx, `select coalesce(sum(size_bytes), 0) from attachments where user_id = $1::uuid`, userID).Scan(&used)
+	return used, err
+}
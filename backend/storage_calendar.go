@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetCalendarToken stores the hash of a user's calendar feed token, replacing any previous one.
+func (s *dbStore) SetCalendarToken(ctx context.Context, userID string, tokenHash []byte) (time.Time, error) {
+	var createdAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		insert into calendar_feed_tokens (user_id, token_hash, created_at)
+		values ($1::uuid, $2, now())
+		on conflict (user_id) do update set token_hash = excluded.token_hash, created_at = excluded.created_at
+		returning created_at
+	`, userID, tokenHash).Scan(&createdAt)
+	return createdAt, err
+}
+
+func (s *dbStore) RevokeCalendarToken(ctx context.Context, userID string) error {
+	ct, err := s.pool.Exec(ctx, `delete from calendar_feed_tokens where user_id = $1::uuid`, userID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+// UserIDForCalendarToken resolves a feed token hash to its owner.
+func (s *dbStore) UserIDForCalendarToken(ctx context.Context, tokenHash []byte) (string, error) {
+	var userID string
+	err := s.pool.QueryRow(ctx, `select user_id::text from calendar_feed_tokens where token_hash = $1`, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errNotFound
+	}
+	return userID, err
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const contactColumns = `c.id::text, c.name, c.email, c.phone, c.linkedin, c.company, c.role, c.notes, c.created_at, c.updated_at`
+
+func scanContact(row pgx.Row, extra ...any) (Contact, error) {
+	var c Contact
+	dest := append([]any{&c.ID, &c.Name, &c.Email, &c.Phone, &c.LinkedIn, &c.Company, &c.Role, &c.Notes, &c.CreatedAt, &c.UpdatedAt}, extra...)
+	err := row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Contact{}, errNotFound
+	}
+	return c, err
+}
+
+func (s *dbStore) ListContacts(ctx context.Context, userID string) ([]Contact, error) {
+	rows, err := s.pool.Query(ctx, `
+		select `+contactColumns+`
+		from contacts c
+		where c.user_id = $1::uuid
+		order by lower(c.name), c.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Contact{}
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (s *dbStore) CreateContact(ctx context.Context, userID string, c Contact) (Contact, error) {
+	if err := normalizeContact(&c); err != nil {
+		return Contact{}, err
+	}
+	return scanContact(s.pool.QueryRow(ctx, `
+		insert into contacts as c (user_id, name, email, phone, linkedin, company, role, notes)
+		values ($1::uuid, $2, $3, $4, $5, $6, $7, $8)
+		returning `+contactColumns,
+		userID, c.Name, c.Email, c.Phone, c.LinkedIn, c.Company, c.Role, c.Notes))
+}
+
+func (s *dbStore) GetContact(ctx context.Context, userID, id string) (Contact, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return Contact{}, errNotFound
+	}
+	return scanContact(s.pool.QueryRow(ctx, `
+		select `+contactColumns+`
+		from contacts c
+		where c.user_id = $1::uuid and c.id = $2::uuid
+	`, userID, id))
+}
+
+func (s *dbStore) UpdateContact(ctx context.Context, userID string, c Contact) (Contact, error) {
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return Contact{}, errNotFound
+	}
+	if err := normalizeContact(&c); err != nil {
+		return Contact{}, err
+	}
+	return scanContact(s.pool.QueryRow(ctx, `
+		update contacts c
+		set name = $3, email = $4, phone = $5, linkedin = $6, company = $7, role = $8, notes = $9, updated_at = now()
+		where c.user_id = $1::uuid and c.id = $2::uuid
+		returning `+contactColumns,
+		userID, c.ID, c.Name, c.Email, c.Phone, c.LinkedIn, c.Company, c.Role, c.Notes))
+}
+
+// DeleteContact removes a contact and its links to applications.
+func (s *dbStore) DeleteContact(ctx context.Context, userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errNotFound
+	}
+	ct, err := s.pool.Exec(ctx, `delete from contacts where user_id = $1::uuid and id = $2::uuid`, userID, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+func (s *dbStore) ListApplicationContacts(ctx context.Context, userID, appID string) ([]LinkedContact, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return nil, errNotFound
+	}
+	var exists bool
+	err := s.pool.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, appID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		select `+contactColumns+`, ac.is_primary
+		from application_contacts ac
+		join contacts c on c.id = ac.contact_id
+		where ac.user_id = $1::uuid and ac.application_id = $2::uuid
+		order by ac.is_primary desc, lower(c.name), c.id
+	`, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []LinkedContact{}
+	for rows.Next() {
+		var lc LinkedContact
+		lc.Contact, err = scanContact(rows, &lc.Primary)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, lc)
+	}
+	return out, rows.Err()
+}
+
+// LinkContact links a contact to an application, or updates an existing link. primary=true makes
+// it the application's primary contact (demoting any other); primary=false demotes it; nil keeps
+// an existing link's flag and makes a new link primary if the application has none yet.
+func (s *dbStore) LinkContact(ctx context.Context, userID, appID, contactID string, primary *bool) (LinkedContact, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return LinkedContact{}, errLinkApplicationNotFound
+	}
+	if _, err := uuid.Parse(contactID); err != nil {
+		return LinkedContact{}, errLinkContactNotFound
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return LinkedContact{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var ok bool
+	err = tx.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null for update
+	`, userID, appID).Scan(&ok)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LinkedContact{}, errLinkApplicationNotFound
+	} else if err != nil {
+		return LinkedContact{}, err
+	}
+	err = tx.QueryRow(ctx, `select true from contacts where user_id = $1::uuid and id = $2::uuid`, userID, contactID).Scan(&ok)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LinkedContact{}, errLinkContactNotFound
+	} else if err != nil {
+		return LinkedContact{}, err
+	}
+
+	if primary != nil && *primary {
+		_, err = tx.Exec(ctx, `
+			update application_contacts set is_primary = false
+			where application_id = $1::uuid and contact_id <> $2::uuid and is_primary
+		`, appID, contactID)
+		if err != nil {
+			return LinkedContact{}, err
+		}
+	}
+	_, err = tx.Exec(ctx, `
+		insert into application_contacts (application_id, contact_id, user_id, is_primary)
+		values ($1::uuid, $2::uuid, $3::uuid,
+		        coalesce($4::boolean, not exists (select 1 from application_contacts where application_id = $1::uuid and is_primary)))
+		on conflict (application_id, contact_id) do update
+		set is_primary = coalesce($4::boolean, application_contacts.is_primary)
+	`, appID, contactID, userID, primary)
+	if err != nil {
+		return LinkedContact{}, err
+	}
+
+	var lc LinkedContact
+	lc.Contact, err = scanContact(tx.QueryRow(ctx, `
+		select `+contactColumns+`, ac.is_primary
+		from application_contacts ac
+		join contacts c on c.id = ac.contact_id
+		where ac.application_id = $1::uuid and ac.contact_id = $2::uuid
+	`, appID, contactID), &lc.Primary)
+	if err != nil {
+		return LinkedContact{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return LinkedContact{}, err
+	}
+	return lc, nil
+}
+
+func (s *dbStore) UnlinkContact(ctx context.Context, userID, appID, contactID string) error {
+	if _, err := uuid.Parse(appID); err != nil {
+		return errNotFound
+	}
+	if _, err := uuid.Parse(contactID); err != nil {
+		return errNotFound
+	}
+	ct, err := s.pool.Exec(ctx, `
+		delete from application_contacts
+		where user_id = $1::uuid and application_id = $2::uuid and contact_id = $3::uuid
+	`, userID, appID, contactID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+// PrimaryContact returns the primary contact linked to an application, or errNotFound.
+func (s *dbStore) PrimaryContact(ctx context.Context, userID, appID string) (Contact, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Contact{}, errNotFound
+	}
+	return scanContact(s.pool.QueryRow(ctx, `
+		select `+contactColumns+`
+		from application_contacts ac
+		join contacts c on c.id = ac.contact_id
+		where ac.user_id = $1::uuid and ac.application_id = $2::uuid and ac.is_primary
+	`, userID, appID))
+}
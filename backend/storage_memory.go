@@ -0,0 +1,1169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryStore is an in-process Store for local development and tests.
+// Data is lost when the process exits.
+type memoryStore struct {
+	mu        sync.RWMutex
+	apps      map[string]*memApplication  // keyed by application id
+	profiles  map[string]memProfile       // keyed by user id
+	calendar  map[string]memCalendarToken // keyed by user id
+	deletions map[string]memDeletionToken // keyed by user id
+	contacts  map[string]*memContact      // keyed by contact id
+	links     map[string][]memContactLink // application id -> linked contacts
+	tags      map[string]map[string]*Tag  // user id -> tag key -> tag
+	files     map[string][]Attachment     // application id -> attachments
+	renders   map[string][]PDFRender      // application id -> renders, oldest first
+	offers    map[string]*Offer           // application id -> offer
+	nextRevID int64
+}
+
+type memApplication struct {
+	userID    string
+	app       Application
+	createdAt time.Time
+	updatedAt time.Time
+	deletedAt *time.Time
+	tagKeys   []string
+	history   []StatusChange
+	revisions []memRevision
+}
+
+type memProfile struct {
+	raw     json.RawMessage
+	version int64
+}
+
+type memContact struct {
+	userID  string
+	contact Contact
+}
+
+type memCalendarToken struct {
+	hash      []byte
+	createdAt time.Time
+}
+
+type memDeletionToken struct {
+	hash      []byte
+	expiresAt time.Time
+}
+
+type memRevision struct {
+	summary RevisionSummary
+	resume  []byte
+	cover   []byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		apps:      map[string]*memApplication{},
+		profiles:  map[string]memProfile{},
+		calendar:  map[string]memCalendarToken{},
+		deletions: map[string]memDeletionToken{},
+		contacts:  map[string]*memContact{},
+		links:     map[string][]memContactLink{},
+		tags:      map[string]map[string]*Tag{},
+		files:     map[string][]Attachment{},
+		renders:   map[string][]PDFRender{},
+		offers:    map[string]*Offer{},
+	}
+}
+
+func (s *memoryStore) Close() {}
+
+// lookup returns the live (not trashed) application owned by userID. Callers must hold s.mu.
+func (s *memoryStore) lookup(userID, id string) (*memApplication, error) {
+	a, ok := s.apps[id]
+	if !ok || a.userID != userID || a.deletedAt != nil {
+		return nil, errNotFound
+	}
+	return a, nil
+}
+
+// statusChangedAt is when the application last changed status (history always has a first entry).
+func (a *memApplication) statusChangedAt() time.Time {
+	return a.history[len(a.history)-1].ChangedAt
+}
+
+// snapshot returns a copy of the stored application with its tags and computed next action.
+// Callers must hold s.mu.
+func (s *memoryStore) snapshot(a *memApplication) (Application, error) {
+	out, _, _, err := copyApplication(a.app)
+	if err != nil {
+		return Application{}, err
+	}
+	out.Tags = s.resolveTags(a)
+	applyNextAction(&out, a.statusChangedAt(), time.Now())
+	return out, nil
+}
+
+// setTags replaces an application's tags, creating or recoloring the user's tags as needed.
+// Callers must hold s.mu for writing.
+func (s *memoryStore) setTags(a *memApplication, tags []Tag) []Tag {
+	userTags := s.tags[a.userID]
+	if userTags == nil {
+		userTags = map[string]*Tag{}
+		s.tags[a.userID] = userTags
+	}
+	a.tagKeys = a.tagKeys[:0]
+	for _, t := range tags {
+		key := tagKey(t.Name)
+		existing, ok := userTags[key]
+		if !ok {
+			existing = &Tag{Name: t.Name, Color: defaultTagColor}
+			userTags[key] = existing
+		}
+		if t.Color != "" {
+			existing.Color = t.Color
+		}
+		a.tagKeys = append(a.tagKeys, key)
+	}
+	return s.resolveTags(a)
+}
+
+func (s *memoryStore) resolveTags(a *memApplication) []Tag {
+	out := make([]Tag, 0, len(a.tagKeys))
+	for _, key := range a.tagKeys {
+		out = append(out, *s.tags[a.userID][key])
+	}
+	sortTags(out)
+	return out
+}
+
+func (s *memoryStore) appendRevision(a *memApplication, source string, restoredFrom *int64, resume, cover []byte) {
+	s.nextRevID++
+	a.revisions = append(a.revisions, memRevision{
+		summary: RevisionSummary{
+			ID:           s.nextRevID,
+			Source:       source,
+			RestoredFrom: restoredFrom,
+			CreatedAt:    time.Now(),
+		},
+		resume: resume,
+		cover:  cover,
+	})
+}
+
+func (s *memoryStore) ListApplicationSummaries(ctx context.Context, userID string, q ApplicationListQuery) (ApplicationPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := map[string]bool{}
+	for _, st := range q.Statuses {
+		statuses[st] = true
+	}
+	search := strings.ToLower(q.Search)
+
+	var matched []ApplicationSummary
+	for _, a := range s.apps {
+		if a.userID != userID || (a.deletedAt != nil) != q.Trash {
+			continue
+		}
+		if len(statuses) > 0 && !statuses[a.app.ApplicationStatus] {
+			continue
+		}
+		if q.Company != "" && !strings.EqualFold(a.app.Company, q.Company) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(a.app.JobTitle+" "+a.app.Company+" "+a.app.JobDescription), search) {
+			continue
+		}
+		if !containsAll(a.tagKeys, q.Tags) {
+			continue
+		}
+		matched = append(matched, ApplicationSummary{
+			ID:                a.app.ID,
+			JobTitle:          a.app.JobTitle,
+			Company:           a.app.Company,
+			ApplicationStatus: a.app.ApplicationStatus,
+			CreatedAt:         a.createdAt,
+			UpdatedAt:         a.updatedAt,
+			DeletedAt:         a.deletedAt,
+			Tags:              s.resolveTags(a),
+			ParentID:          a.app.ParentID,
+		})
+	}
+
+	// less orders by (sort key, id) in the requested direction.
+	less := func(ka, ida, kb, idb string) bool {
+		if ka != kb {
+			return (ka < kb) != q.Desc
+		}
+		return (ida < idb) != q.Desc
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return less(q.sortKey(matched[i]), matched[i].ID, q.sortKey(matched[j]), matched[j].ID)
+	})
+
+	out := []ApplicationSummary{}
+	for _, a := range matched {
+		if q.After != nil && !less(q.After.Key, q.After.ID, q.sortKey(a), a.ID) {
+			continue
+		}
+		out = append(out, a)
+		if len(out) > q.Limit {
+			break
+		}
+	}
+	return q.page(out), nil
+}
+
+func (s *memoryStore) CreateApplication(ctx context.Context, userID string, app Application) (Application, error) {
+	status, err := normalizeApplicationStatus(app.ApplicationStatus)
+	if err != nil {
+		return Application{}, err
+	}
+	app.ApplicationStatus = status
+	app.ID = uuid.New().String()
+	app.Version = 1
+	now := time.Now()
+	if err := normalizeSchedule(&app); err != nil {
+		return Application{}, err
+	}
+	defaultAppliedOn(&app, statusSaved, now)
+	if app.Tags, err = normalizeTags(app.Tags); err != nil {
+		return Application{}, err
+	}
+
+	stored, resume, cover, err := copyApplication(app)
+	if err != nil {
+		return Application{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := &memApplication{
+		userID:    userID,
+		app:       stored,
+		createdAt: now,
+		updatedAt: now,
+		history:   []StatusChange{{ToStatus: status, ChangedAt: now}},
+	}
+	s.appendRevision(a, revisionSourceManual, nil, resume, cover)
+	s.apps[app.ID] = a
+	app.Tags = s.setTags(a, app.Tags)
+	applyNextAction(&app, now, now)
+	return app, nil
+}
+
+func (s *memoryStore) ImportApplication(ctx context.Context, userID string, app Application) (Application, bool, error) {
+	status, err := normalizeApplicationStatus(app.ApplicationStatus)
+	if err != nil {
+		return Application{}, false, err
+	}
+	app.ApplicationStatus = status
+	if _, err := uuid.Parse(app.ID); err != nil {
+		app.ID = uuid.New().String()
+	}
+	app.ParentID = ""
+	now := time.Now()
+	if err := normalizeSchedule(&app); err != nil {
+		return Application{}, false, err
+	}
+	defaultAppliedOn(&app, statusSaved, now)
+	if app.Tags, err = normalizeTags(app.Tags); err != nil {
+		return Application{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.apps[app.ID]; ok {
+		if existing.userID == userID {
+			return app, false, nil
+		}
+		app.ID = importFallbackID(userID, app.ID)
+		if _, ok := s.apps[app.ID]; ok {
+			return app, false, nil
+		}
+	}
+	app.Version = 1
+
+	stored, resume, cover, err := copyApplication(app)
+	if err != nil {
+		return Application{}, false, err
+	}
+	a := &memApplication{
+		userID:    userID,
+		app:       stored,
+		createdAt: now,
+		updatedAt: now,
+		history:   []StatusChange{{ToStatus: status, ChangedAt: now}},
+	}
+	s.appendRevision(a, revisionSourceImport, nil, resume, cover)
+	s.apps[app.ID] = a
+	app.Tags = s.setTags(a, app.Tags)
+	applyNextAction(&app, now, now)
+	return app, true, nil
+}
+
+func (s *memoryStore) GetApplication(ctx context.Context, userID, id string) (Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, err := s.lookup(userID, id)
+	if err != nil {
+		return Application{}, err
+	}
+	return s.snapshot(a)
+}
+
+func (s *memoryStore) UpdateApplication(ctx context.Context, userID string, app Application, source string) (Application, error) {
+	if strings.TrimSpace(app.ID) == "" {
+		return Application{}, fmt.Errorf("id required")
+	}
+	status, err := normalizeUpdateStatus(app.ApplicationStatus)
+	if err != nil {
+		return Application{}, err
+	}
+	app.ApplicationStatus = status
+	now := time.Now()
+	if err := normalizeSchedule(&app); err != nil {
+		return Application{}, err
+	}
+	if app.Tags != nil {
+		if app.Tags, err = normalizeTags(app.Tags); err != nil {
+			return Application{}, err
+		}
+	}
+
+	stored, resume, cover, err := copyApplication(app)
+	if err != nil {
+		return Application{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.lookup(userID, app.ID)
+	if err != nil {
+		return Application{}, err
+	}
+	if a.app.Version != app.Version {
+		return Application{}, errVersionConflict
+	}
+	current := a.app.ApplicationStatus
+	if status == "" {
+		status = current
+		app.ApplicationStatus = current
+		stored.ApplicationStatus = current
+	}
+	if err := checkStatusTransition(current, status); err != nil {
+		return Application{}, err
+	}
+	defaultAppliedOn(&app, current, now)
+	stored.AppliedOn = app.AppliedOn
+
+	if current != status {
+		from := current
+		a.history = append(a.history, StatusChange{FromStatus: &from, ToStatus: status, ChangedAt: now})
+	}
+	last := a.revisions[len(a.revisions)-1]
+	if !bytes.Equal(last.resume, resume) || !bytes.Equal(last.cover, cover) {
+		s.appendRevision(a, source, nil, resume, cover)
+	}
+	app.Version++
+	app.ParentID = a.app.ParentID
+	stored.Version = app.Version
+	stored.ParentID = a.app.ParentID
+	a.app = stored
+	a.updatedAt = now
+	if app.Tags != nil {
+		app.Tags = s.setTags(a, app.Tags)
+	} else {
+		app.Tags = s.resolveTags(a)
+	}
+	applyNextAction(&app, a.statusChangedAt(), now)
+	return app, nil
+}
+
+func (s *memoryStore) DeleteApplication(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.lookup(userID, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	a.deletedAt = &now
+	return nil
+}
+
+func (s *memoryStore) RestoreApplication(ctx context.Context, userID, id string) (Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.apps[id]
+	if !ok || a.userID != userID || a.deletedAt == nil {
+		return Application{}, errNotFound
+	}
+	a.deletedAt = nil
+	return s.snapshot(a)
+}
+
+func (s *memoryStore) PurgeTrash(ctx context.Context, cutoff time.Time) (int64, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	var blobKeys []string
+	for id, a := range s.apps {
+		if a.deletedAt != nil && a.deletedAt.Before(cutoff) {
+			for _, f := range s.files[id] {
+				blobKeys = append(blobKeys, f.BlobKey)
+			}
+			for _, p := range s.renders[id] {
+				blobKeys = append(blobKeys, p.ResumeBlobKey, p.CoverBlobKey)
+			}
+			delete(s.apps, id)
+			delete(s.links, id)
+			delete(s.files, id)
+			delete(s.renders, id)
+			delete(s.offers, id)
+			n++
+		}
+	}
+	return n, blobKeys, nil
+}
+
+func (s *memoryStore) ListStatusHistory(ctx context.Context, userID, appID string) ([]StatusChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]StatusChange{}, a.history...), nil
+}
+
+func (s *memoryStore) ListOpenSchedules(ctx context.Context, userID string) ([]ApplicationSchedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []ApplicationSchedule{}
+	for _, a := range s.apps {
+		if a.userID != userID || a.deletedAt != nil || terminalStatuses[a.app.ApplicationStatus] {
+			continue
+		}
+		out = append(out, ApplicationSchedule{
+			ID:                a.app.ID,
+			JobTitle:          a.app.JobTitle,
+			Company:           a.app.Company,
+			ApplicationStatus: a.app.ApplicationStatus,
+			Deadline:          a.app.Deadline,
+			AppliedOn:         a.app.AppliedOn,
+			Interviews:        append([]InterviewRound{}, a.app.Interviews...),
+			StatusChangedAt:   a.statusChangedAt(),
+		})
+	}
+	return out, nil
+}
+
+func (s *memoryStore) ListRevisions(ctx context.Context, userID, appID string) ([]RevisionSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RevisionSummary, 0, len(a.revisions))
+	for i := len(a.revisions) - 1; i >= 0; i-- {
+		out = append(out, a.revisions[i].summary)
+	}
+	return out, nil
+}
+
+func (s *memoryStore) GetRevision(ctx context.Context, userID, appID string, revID int64) (Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return Revision{}, err
+	}
+	for _, rev := range a.revisions {
+		if rev.summary.ID != revID {
+			continue
+		}
+		out := Revision{RevisionSummary: rev.summary}
+		if err := json.Unmarshal(rev.resume, &out.Resume); err != nil {
+			return Revision{}, err
+		}
+		if len(rev.cover) != 0 {
+			var cl CoverLetter
+			if err := json.Unmarshal(rev.cover, &cl); err != nil {
+				return Revision{}, err
+			}
+			out.CoverLetter = &cl
+		}
+		return out, nil
+	}
+	return Revision{}, errNotFound
+}
+
+func (s *memoryStore) RestoreRevision(ctx context.Context, userID, appID string, revID, ifMatch int64) (Application, error) {
+	rev, err := s.GetRevision(ctx, userID, appID, revID)
+	if err != nil {
+		return Application{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return Application{}, err
+	}
+	if a.app.Version != ifMatch {
+		return Application{}, errVersionConflict
+	}
+	next := a.app
+	next.Resume = rev.Resume
+	next.CoverLetter = rev.CoverLetter
+	next.Version++
+	stored, resume, cover, err := copyApplication(next)
+	if err != nil {
+		return Application{}, err
+	}
+	a.app = stored
+	a.updatedAt = time.Now()
+	s.appendRevision(a, revisionSourceRestore, &revID, resume, cover)
+	return s.snapshot(a)
+}
+
+func (s *memoryStore) SetCalendarToken(ctx context.Context, userID string, tokenHash []byte) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.calendar[userID] = memCalendarToken{hash: append([]byte(nil), tokenHash...), createdAt: now}
+	return now, nil
+}
+
+func (s *memoryStore) RevokeCalendarToken(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.calendar[userID]; !ok {
+		return errNotFound
+	}
+	delete(s.calendar, userID)
+	return nil
+}
+
+func (s *memoryStore) UserIDForCalendarToken(ctx context.Context, tokenHash []byte) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for userID, t := range s.calendar {
+		if bytes.Equal(t.hash, tokenHash) {
+			return userID, nil
+		}
+	}
+	return "", errNotFound
+}
+
+func (s *memoryStore) SetAccountDeletionToken(ctx context.Context, userID string, tokenHash []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deletions[userID] = memDeletionToken{hash: append([]byte(nil), tokenHash...), expiresAt: expiresAt}
+	return nil
+}
+
+func (s *memoryStore) DeleteAccount(ctx context.Context, userID string, tokenHash []byte) (AccountDeletionReceipt, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	t, ok := s.deletions[userID]
+	if !ok || !bytes.Equal(t.hash, tokenHash) || !now.Before(t.expiresAt) {
+		return AccountDeletionReceipt{}, nil, errInvalidDeletionToken
+	}
+	delete(s.deletions, userID)
+
+	receipt := AccountDeletionReceipt{UserID: userID, DeletedAt: now}
+	var blobKeys []string
+	for id, a := range s.apps {
+		if a.userID != userID {
+			continue
+		}
+		for _, f := range s.files[id] {
+			blobKeys = append(blobKeys, f.BlobKey)
+			receipt.Attachments++
+		}
+		for _, p := range s.renders[id] {
+			blobKeys = append(blobKeys, p.ResumeBlobKey, p.CoverBlobKey)
+			receipt.Renders++
+		}
+		if _, ok := s.offers[id]; ok {
+			receipt.Offers++
+		}
+		receipt.StatusChanges += int64(len(a.history))
+		for _, rev := range a.revisions {
+			receipt.Revisions++
+			if rev.summary.Source == revisionSourceAIResume || rev.summary.Source == revisionSourceAICoverLetter {
+				receipt.AIRevisions++
+			}
+		}
+		delete(s.apps, id)
+		delete(s.links, id)
+		delete(s.files, id)
+		delete(s.renders, id)
+		delete(s.offers, id)
+		receipt.Applications++
+	}
+	for id, c := range s.contacts {
+		if c.userID == userID {
+			delete(s.contacts, id)
+			receipt.Contacts++
+		}
+	}
+	receipt.Tags = int64(len(s.tags[userID]))
+	delete(s.tags, userID)
+	_, receipt.Profile = s.profiles[userID]
+	delete(s.profiles, userID)
+	_, receipt.CalendarFeed = s.calendar[userID]
+	delete(s.calendar, userID)
+	return receipt, blobKeys, nil
+}
+
+func (s *memoryStore) GetProfile(ctx context.Context, userID string) (json.RawMessage, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.profiles[userID]
+	if !ok {
+		return nil, 0, errNotFound
+	}
+	return append(json.RawMessage(nil), p.raw...), p.version, nil
+}
+
+func (s *memoryStore) UpsertProfile(ctx context.Context, userID string, profile json.RawMessage, ifMatch int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.profiles[userID]
+	if ifMatch != p.version {
+		return 0, errVersionConflict
+	}
+	s.profiles[userID] = memProfile{raw: append(json.RawMessage(nil), profile...), version: p.version + 1}
+	return p.version + 1, nil
+}
+
+// copyApplication deep-copies an application via its JSON form so callers can't mutate
+// stored state. It also returns the encoded resume and cover letter for revision tracking.
+func copyApplication(app Application) (Application, []byte, []byte, error) {
+	resume, err := json.Marshal(app.Resume)
+	if err != nil {
+		return Application{}, nil, nil, err
+	}
+	var cover []byte
+	if app.CoverLetter != nil {
+		cover, err = json.Marshal(app.CoverLetter)
+		if err != nil {
+			return Application{}, nil, nil, err
+		}
+	}
+
+	out := app
+	out.Resume = ResumeData{}
+	if err := json.Unmarshal(resume, &out.Resume); err != nil {
+		return Application{}, nil, nil, err
+	}
+	out.CoverLetter = nil
+	if cover != nil {
+		var cl CoverLetter
+		if err := json.Unmarshal(cover, &cl); err != nil {
+			return Application{}, nil, nil, err
+		}
+		out.CoverLetter = &cl
+	}
+	return out, resume, cover, nil
+}
+
+type memContactLink struct {
+	contactID string
+	primary   bool
+}
+
+func (s *memoryStore) ListTagCounts(ctx context.Context, userID string) ([]TagCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := map[string]int{}
+	for _, a := range s.apps {
+		if a.userID != userID || a.deletedAt != nil {
+			continue
+		}
+		for _, key := range a.tagKeys {
+			counts[key]++
+		}
+	}
+	out := []TagCount{}
+	for key, n := range counts {
+		out = append(out, TagCount{Tag: *s.tags[userID][key], Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return tagKey(out[i].Name) < tagKey(out[j].Name)
+	})
+	return out, nil
+}
+
+func (s *memoryStore) ListContacts(ctx context.Context, userID string) ([]Contact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []Contact{}
+	for _, c := range s.contacts {
+		if c.userID == userID {
+			out = append(out, c.contact)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := strings.ToLower(out[i].Name), strings.ToLower(out[j].Name)
+		if a != b {
+			return a < b
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (s *memoryStore) CreateContact(ctx context.Context, userID string, c Contact) (Contact, error) {
+	if err := normalizeContact(&c); err != nil {
+		return Contact{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.ID = uuid.New().String()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+	s.contacts[c.ID] = &memContact{userID: userID, contact: c}
+	return c, nil
+}
+
+func (s *memoryStore) GetContact(ctx context.Context, userID, id string) (Contact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.contacts[id]
+	if !ok || c.userID != userID {
+		return Contact{}, errNotFound
+	}
+	return c.contact, nil
+}
+
+func (s *memoryStore) UpdateContact(ctx context.Context, userID string, c Contact) (Contact, error) {
+	if err := normalizeContact(&c); err != nil {
+		return Contact{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.contacts[c.ID]
+	if !ok || existing.userID != userID {
+		return Contact{}, errNotFound
+	}
+	c.CreatedAt = existing.contact.CreatedAt
+	c.UpdatedAt = time.Now()
+	existing.contact = c
+	return c, nil
+}
+
+func (s *memoryStore) DeleteContact(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.contacts[id]
+	if !ok || c.userID != userID {
+		return errNotFound
+	}
+	delete(s.contacts, id)
+	for appID, links := range s.links {
+		s.links[appID] = slices.DeleteFunc(links, func(l memContactLink) bool { return l.contactID == id })
+	}
+	return nil
+}
+
+func (s *memoryStore) ListApplicationContacts(ctx context.Context, userID, appID string) ([]LinkedContact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return nil, err
+	}
+	out := []LinkedContact{}
+	for _, l := range s.links[appID] {
+		out = append(out, LinkedContact{Contact: s.contacts[l.contactID].contact, Primary: l.primary})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Primary != out[j].Primary {
+			return out[i].Primary
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out, nil
+}
+
+func (s *memoryStore) LinkContact(ctx context.Context, userID, appID, contactID string, primary *bool) (LinkedContact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return LinkedContact{}, errLinkApplicationNotFound
+	}
+	c, ok := s.contacts[contactID]
+	if !ok || c.userID != userID {
+		return LinkedContact{}, errLinkContactNotFound
+	}
+
+	links := s.links[appID]
+	idx, hasPrimary := -1, false
+	for i, l := range links {
+		if l.contactID == contactID {
+			idx = i
+		}
+		if l.primary {
+			hasPrimary = true
+		}
+	}
+	if idx < 0 {
+		links = append(links, memContactLink{contactID: contactID, primary: !hasPrimary})
+		idx = len(links) - 1
+	}
+	if primary != nil {
+		if *primary {
+			for i := range links {
+				links[i].primary = false
+			}
+		}
+		links[idx].primary = *primary
+	}
+	s.links[appID] = links
+	return LinkedContact{Contact: c.contact, Primary: links[idx].primary}, nil
+}
+
+func (s *memoryStore) UnlinkContact(ctx context.Context, userID, appID, contactID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return err
+	}
+	links := s.links[appID]
+	kept := slices.DeleteFunc(slices.Clone(links), func(l memContactLink) bool { return l.contactID == contactID })
+	if len(kept) == len(links) {
+		return errNotFound
+	}
+	s.links[appID] = kept
+	return nil
+}
+
+func (s *memoryStore) PrimaryContact(ctx context.Context, userID, appID string) (Contact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.apps[appID]
+	if !ok || a.userID != userID {
+		return Contact{}, errNotFound
+	}
+	for _, l := range s.links[appID] {
+		if l.primary {
+			return s.contacts[l.contactID].contact, nil
+		}
+	}
+	return Contact{}, errNotFound
+}
+
+func containsAll(have, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(have, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *memoryStore) ListAttachments(ctx context.Context, userID, appID string) ([]Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return nil, err
+	}
+	return append([]Attachment{}, s.files[appID]...), nil
+}
+
+func (s *memoryStore) CreateAttachment(ctx context.Context, userID string, a Attachment, quotaBytes int64) (Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(userID, a.ApplicationID); err != nil {
+		return Attachment{}, err
+	}
+	if s.attachmentUsage(userID)+a.Size > quotaBytes {
+		return Attachment{}, errAttachmentQuota
+	}
+	a.CreatedAt = time.Now().UTC()
+	s.files[a.ApplicationID] = append(s.files[a.ApplicationID], a)
+	return a, nil
+}
+
+func (s *memoryStore) GetAttachment(ctx context.Context, userID, appID, id string) (Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return Attachment{}, err
+	}
+	for _, f := range s.files[appID] {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return Attachment{}, errNotFound
+}
+
+func (s *memoryStore) DeleteAttachment(ctx context.Context, userID, appID, id string) (Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return Attachment{}, err
+	}
+	files := s.files[appID]
+	i := slices.IndexFunc(files, func(f Attachment) bool { return f.ID == id })
+	if i < 0 {
+		return Attachment{}, errNotFound
+	}
+	deleted := files[i]
+	s.files[appID] = slices.Delete(slices.Clone(files), i, i+1)
+	return deleted, nil
+}
+
+func (s *memoryStore) AttachmentUsage(ctx context.Context, userID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.attachmentUsage(userID), nil
+}
+
+// attachmentUsage totals the user's attachments, trashed applications included. Callers must hold s.mu.
+func (s *memoryStore) attachmentUsage(userID string) int64 {
+	var used int64
+	for appID, files := range s.files {
+		if a, ok := s.apps[appID]; ok && a.userID == userID {
+			for _, f := range files {
+				used += f.Size
+			}
+		}
+	}
+	return used
+}
+
+func (s *memoryStore) CreateRender(ctx context.Context, userID string, p PDFRender) (PDFRender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(userID, p.ApplicationID); err != nil {
+		return PDFRender{}, err
+	}
+	p.CreatedAt = time.Now().UTC()
+	p.Input = append(json.RawMessage{}, p.Input...)
+	s.renders[p.ApplicationID] = append(s.renders[p.ApplicationID], p)
+	return p, nil
+}
+
+func (s *memoryStore) ListRenders(ctx context.Context, userID, appID string) ([]PDFRender, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return nil, err
+	}
+	renders := s.renders[appID]
+	out := make([]PDFRender, 0, len(renders))
+	for i := len(renders) - 1; i >= 0; i-- {
+		p := renders[i]
+		p.Input = nil
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (s *memoryStore) GetRender(ctx context.Context, userID, appID, id string) (PDFRender, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return PDFRender{}, err
+	}
+	for _, p := range s.renders[appID] {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return PDFRender{}, errNotFound
+}
+
+// offerSnapshot copies a stored offer, filling in the application's title and company.
+// Callers must hold s.mu.
+func (s *memoryStore) offerSnapshot(a *memApplication, o *Offer) Offer {
+	out := *o
+	out.JobTitle = a.app.JobTitle
+	out.Company = a.app.Company
+	out.Negotiation = append([]NegotiationEvent{}, o.Negotiation...)
+	return out
+}
+
+func (s *memoryStore) GetOffer(ctx context.Context, userID, appID string) (Offer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return Offer{}, err
+	}
+	o, ok := s.offers[appID]
+	if !ok {
+		return Offer{}, errNotFound
+	}
+	return s.offerSnapshot(a, o), nil
+}
+
+func (s *memoryStore) PutOffer(ctx context.Context, userID, appID string, o Offer) (Offer, bool, error) {
+	if err := normalizeOffer(&o); err != nil {
+		return Offer{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return Offer{}, false, err
+	}
+	now := time.Now().UTC()
+	existing, ok := s.offers[appID]
+	o.ApplicationID = appID
+	o.UpdatedAt = now
+	if ok {
+		o.ID = existing.ID
+		o.CreatedAt = existing.CreatedAt
+		o.Negotiation = existing.Negotiation
+	} else {
+		o.ID = uuid.NewString()
+		o.CreatedAt = now
+		o.Negotiation = []NegotiationEvent{}
+	}
+	s.offers[appID] = &o
+	return s.offerSnapshot(a, &o), !ok, nil
+}
+
+func (s *memoryStore) DeleteOffer(ctx context.Context, userID, appID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lookup(userID, appID); err != nil {
+		return err
+	}
+	if _, ok := s.offers[appID]; !ok {
+		return errNotFound
+	}
+	delete(s.offers, appID)
+	return nil
+}
+
+func (s *memoryStore) AppendNegotiation(ctx context.Context, userID, appID string, e NegotiationEvent) (Offer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.lookup(userID, appID)
+	if err != nil {
+		return Offer{}, err
+	}
+	o, ok := s.offers[appID]
+	if !ok {
+		return Offer{}, errNotFound
+	}
+	if err := normalizeNegotiationEvent(&e, o.Currency, time.Now()); err != nil {
+		return Offer{}, err
+	}
+	o.Negotiation = append(slices.Clone(o.Negotiation), e)
+	o.UpdatedAt = time.Now().UTC()
+	return s.offerSnapshot(a, o), nil
+}
+
+func (s *memoryStore) ListOffers(ctx context.Context, userID string, ids []string) ([]Offer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []Offer{}
+	for appID, o := range s.offers {
+		a, err := s.lookup(userID, appID)
+		if err != nil {
+			continue
+		}
+		if len(ids) > 0 && !slices.Contains(ids, o.ID) {
+			continue
+		}
+		out = append(out, s.offerSnapshot(a, o))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (s *memoryStore) Analytics(ctx context.Context, userID string, q AnalyticsQuery) (Analytics, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var apps []analyticsApp
+	for _, a := range s.apps {
+		if a.userID != userID || a.deletedAt != nil {
+			continue
+		}
+		created := a.createdAt.UTC().Format(dateLayout)
+		if (q.From != "" && created < q.From) || (q.To != "" && created > q.To) {
+			continue
+		}
+		if !containsAll(a.tagKeys, q.Tags) {
+			continue
+		}
+		apps = append(apps, analyticsApp{
+			Status:    a.app.ApplicationStatus,
+			Company:   a.app.Company,
+			JobTitle:  a.app.JobTitle,
+			CreatedAt: a.createdAt,
+			AppliedOn: a.app.AppliedOn,
+			History:   a.history,
+		})
+	}
+	return computeAnalytics(q, apps, time.Now()), nil
+}
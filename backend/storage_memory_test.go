@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testUserID      = "11111111-1111-1111-1111-111111111111"
+	otherTestUserID = "22222222-2222-2222-2222-222222222222"
+)
+
+func createTestApplication(t *testing.T, s Store, userID, status string) Application {
+	t.Helper()
+	app, err := s.CreateApplication(context.Background(), userID, Application{
+		JobTitle:          "Backend Engineer",
+		Company:           "Acme",
+		ApplicationStatus: status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestMemoryStoreApplicationRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+
+	app := createTestApplication(t, s, testUserID, "")
+	if app.Version != 1 || app.ApplicationStatus != statusApplied {
+		t.Fatalf("created version %d status %q, want 1 and applied", app.Version, app.ApplicationStatus)
+	}
+
+	app.Company = "Globex"
+	app.ApplicationStatus = statusInterview
+	updated, err := s.UpdateApplication(ctx, testUserID, app, revisionSourceManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("updated version %d, want 2", updated.Version)
+	}
+
+	got, err := s.GetApplication(ctx, testUserID, app.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Company != "Globex" || got.ApplicationStatus != statusInterview || got.Version != 2 {
+		t.Errorf("got %q/%q version %d, want Globex/interview version 2", got.Company, got.ApplicationStatus, got.Version)
+	}
+
+	history, err := s.ListStatusHistory(ctx, testUserID, app.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[1].ToStatus != statusInterview {
+		t.Errorf("history %+v, want applied then interview", history)
+	}
+}
+
+func TestMemoryStoreUpdateVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	app := createTestApplication(t, s, testUserID, statusApplied)
+
+	app.Company = "Globex"
+	if _, err := s.UpdateApplication(ctx, testUserID, app, revisionSourceManual); err != nil {
+		t.Fatal(err)
+	}
+
+	// app still carries version 1.
+	app.Company = "Initech"
+	if _, err := s.UpdateApplication(ctx, testUserID, app, revisionSourceManual); !errors.Is(err, errVersionConflict) {
+		t.Fatalf("stale update: err = %v, want errVersionConflict", err)
+	}
+	got, err := s.GetApplication(ctx, testUserID, app.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Company != "Globex" {
+		t.Errorf("stale update was applied: company %q", got.Company)
+	}
+}
+
+func TestMemoryStoreUpdateKeepsStatusWhenBlank(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	app := createTestApplication(t, s, testUserID, statusInterview)
+
+	app.ApplicationStatus = ""
+	updated, err := s.UpdateApplication(ctx, testUserID, app, revisionSourceManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ApplicationStatus != statusInterview {
+		t.Errorf("status %q, want interview", updated.ApplicationStatus)
+	}
+}
+
+func TestMemoryStoreApplicationNotFound(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	owned := createTestApplication(t, s, otherTestUserID, statusApplied)
+
+	for name, id := range map[string]string{
+		"unknown id":   uuid.NewString(),
+		"not a uuid":   "not-a-uuid",
+		"other user's": owned.ID,
+		"empty":        "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.GetApplication(ctx, testUserID, id); !isNotFound(err) {
+				t.Errorf("GetApplication: err = %v, want not found", err)
+			}
+			if err := s.DeleteApplication(ctx, testUserID, id); !isNotFound(err) {
+				t.Errorf("DeleteApplication: err = %v, want not found", err)
+			}
+			if _, err := s.RestoreApplication(ctx, testUserID, id); !isNotFound(err) {
+				t.Errorf("RestoreApplication: err = %v, want not found", err)
+			}
+			if _, err := s.ListStatusHistory(ctx, testUserID, id); !isNotFound(err) {
+				t.Errorf("ListStatusHistory: err = %v, want not found", err)
+			}
+			if id != "" {
+				app := Application{ID: id, JobTitle: "x", Company: "y", Version: 1}
+				if _, err := s.UpdateApplication(ctx, testUserID, app, revisionSourceManual); !isNotFound(err) {
+					t.Errorf("UpdateApplication: err = %v, want not found", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTrash(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+	app := createTestApplication(t, s, testUserID, statusApplied)
+
+	if err := s.DeleteApplication(ctx, testUserID, app.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetApplication(ctx, testUserID, app.ID); !isNotFound(err) {
+		t.Errorf("trashed application: err = %v, want not found", err)
+	}
+	if err := s.DeleteApplication(ctx, testUserID, app.ID); !isNotFound(err) {
+		t.Errorf("deleting twice: err = %v, want not found", err)
+	}
+	if _, err := s.RestoreApplication(ctx, testUserID, app.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RestoreApplication(ctx, testUserID, app.ID); !isNotFound(err) {
+		t.Errorf("restoring a live application: err = %v, want not found", err)
+	}
+	if _, err := s.GetApplication(ctx, testUserID, app.ID); err != nil {
+		t.Errorf("restored application: %v", err)
+	}
+}
+
+func TestMemoryStoreProfile(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+
+	if _, _, err := s.GetProfile(ctx, testUserID); !isNotFound(err) {
+		t.Fatalf("missing profile: err = %v, want not found", err)
+	}
+	if _, err := s.UpsertProfile(ctx, testUserID, json.RawMessage(`{"name":"Ada"}`), 1); !errors.Is(err, errVersionConflict) {
+		t.Errorf("updating a missing profile: err = %v, want errVersionConflict", err)
+	}
+
+	version, err := s.UpsertProfile(ctx, testUserID, json.RawMessage(`{"name":"Ada"}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 1 {
+		t.Errorf("created version %d, want 1", version)
+	}
+	if _, err := s.UpsertProfile(ctx, testUserID, json.RawMessage(`{"name":"Grace"}`), 0); !errors.Is(err, errVersionConflict) {
+		t.Errorf("creating twice: err = %v, want errVersionConflict", err)
+	}
+	if version, err = s.UpsertProfile(ctx, testUserID, json.RawMessage(`{"name":"Ada Lovelace"}`), version); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpsertProfile(ctx, testUserID, json.RawMessage(`{"name":"stale"}`), 1); !errors.Is(err, errVersionConflict) {
+		t.Errorf("stale update: err = %v, want errVersionConflict", err)
+	}
+
+	raw, got, err := s.GetProfile(ctx, testUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != version || !jsonEqual(raw, []byte(`{"name":"Ada Lovelace"}`)) {
+		t.Errorf("got %s version %d, want Ada Lovelace version %d", raw, got, version)
+	}
+	if _, _, err := s.GetProfile(ctx, otherTestUserID); !isNotFound(err) {
+		t.Errorf("other user's profile: err = %v, want not found", err)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// offerColumns selects an offer joined to its application (aliased o and a).
+const offerColumns = `o.id::text, o.application_id::text, a.job_title, a.company,
+	o.currency, o.base_salary::float8, o.base_period, o.hours_per_week::float8,
+	o.signing_bonus::float8, o.annual_bonus::float8, o.equity_value::float8, o.equity_vesting_years::float8,
+	o.benefits, o.benefits_value::float8, o.location,
+	coalesce(to_char(o.start_date, 'YYYY-MM-DD'), ''), coalesce(to_char(o.expires_on, 'YYYY-MM-DD'), ''),
+	o.notes, o.negotiation, o.created_at, o.updated_at`
+
+func scanOffer(row pgx.Row) (Offer, error) {
+	var o Offer
+	var negotiation []byte
+	err := row.Scan(&o.ID, &o.ApplicationID, &o.JobTitle, &o.Company,
+		&o.Currency, &o.BaseSalary, &o.BasePeriod, &o.HoursPerWeek,
+		&o.SigningBonus, &o.AnnualBonus, &o.EquityValue, &o.EquityVestingYears,
+		&o.Benefits, &o.BenefitsValue, &o.Location, &o.StartDate, &o.ExpiresOn,
+		&o.Notes, &negotiation, &o.CreatedAt, &o.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Offer{}, errNotFound
+	}
+	if err != nil {
+		return Offer{}, err
+	}
+	if err := json.Unmarshal(negotiation, &o.Negotiation); err != nil {
+		return Offer{}, fmt.Errorf("decode negotiation history: %w", err)
+	}
+	if o.Negotiation == nil {
+		o.Negotiation = []NegotiationEvent{}
+	}
+	return o, nil
+}
+
+func (s *dbStore) GetOffer(ctx context.Context, userID, appID string) (Offer, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Offer{}, errNotFound
+	}
+	return scanOffer(s.pool.QueryRow(ctx, `
+		select `+offerColumns+`
+		from offers o
+		join applications a on a.id = o.application_id
+		where a.user_id = $1::uuid and a.id = $2::uuid and a.deleted_at is null
+	`, userID, appID))
+}
+
+// PutOffer creates or replaces the offer of an application. The negotiation history is kept.
+func (s *dbStore) PutOffer(ctx context.Context, userID, appID string, o Offer) (Offer, bool, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Offer{}, false, errNotFound
+	}
+	if err := normalizeOffer(&o); err != nil {
+		return Offer{}, false, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Offer{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var ok bool
+	err = tx.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null for update
+	`, userID, appID).Scan(&ok)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Offer{}, false, errNotFound
+	} else if err != nil {
+		return Offer{}, false, err
+	}
+
+	var created bool
+	err = tx.QueryRow(ctx, `
+		insert into offers (application_id, user_id, currency, base_salary, base_period, hours_per_week,
+		                    signing_bonus, annual_bonus, equity_value, equity_vesting_years,
+		                    benefits, benefits_value, location, start_date, expires_on, notes)
+		values ($1::uuid, $2::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+		        nullif($14, '')::date, nullif($15, '')::date, $16)
+		on conflict (application_id) do update
+		set currency = excluded.currency, base_salary = excluded.base_salary, base_period = excluded.base_period,
+		    hours_per_week = excluded.hours_per_week, signing_bonus = excluded.signing_bonus,
+		    annual_bonus = excluded.annual_bonus, equity_value = excluded.equity_value,
+		    equity_vesting_years = excluded.equity_vesting_years, benefits = excluded.benefits,
+		    benefits_value = excluded.benefits_value, location = excluded.location,
+		    start_date = excluded.start_date, expires_on = excluded.expires_on, notes = excluded.notes,
+		    updated_at = now()
+		returning (xmax = 0)
+	`, appID, userID, o.Currency, o.BaseSalary, o.BasePeriod, o.HoursPerWeek,
+		o.SigningBonus, o.AnnualBonus, o.EquityValue, o.EquityVestingYears,
+		o.Benefits, o.BenefitsValue, o.Location, o.StartDate, o.ExpiresOn, o.Notes).Scan(&created)
+	if err != nil {
+		return Offer{}, false, err
+	}
+
+	saved, err := scanOffer(tx.QueryRow(ctx, `
+		select `+offerColumns+`
+		from offers o
+		join applications a on a.id = o.application_id
+		where o.application_id = $1::uuid
+	`, appID))
+	if err != nil {
+		return Offer{}, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Offer{}, false, err
+	}
+	return saved, created, nil
+}
+
+func (s *dbStore) DeleteOffer(ctx context.Context, userID, appID string) error {
+	if _, err := uuid.Parse(appID); err != nil {
+		return errNotFound
+	}
+	ct, err := s.pool.Exec(ctx, `
+		delete from offers o
+		using applications a
+		where a.id = o.application_id and a.user_id = $1::uuid and a.id = $2::uuid and a.deleted_at is null
+	`, userID, appID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+// AppendNegotiation adds an event to an offer's negotiation history.
+func (s *dbStore) AppendNegotiation(ctx context.Context, userID, appID string, e NegotiationEvent) (Offer, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Offer{}, errNotFound
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Offer{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var currency string
+	err = tx.QueryRow(ctx, `
+		select o.currency
+		from offers o
+		join applications a on a.id = o.application_id
+		where a.user_id = $1::uuid and a.id = $2::uuid and a.deleted_at is null
+		for update of o
+	`, userID, appID).Scan(&currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Offer{}, errNotFound
+	} else if err != nil {
+		return Offer{}, err
+	}
+	if err := normalizeNegotiationEvent(&e, currency, time.Now()); err != nil {
+		return Offer{}, err
+	}
+	event, err := json.Marshal([]NegotiationEvent{e})
+	if err != nil {
+		return Offer{}, err
+	}
+
+	saved, err := scanOffer(tx.QueryRow(ctx, `
+		with updated as (
+			update offers set negotiation = negotiation || $2::jsonb, updated_at = now()
+			where application_id = $1::uuid
+			returning *
+		)
+		select `+offerColumns+`
+		from updated o
+		join applications a on a.id = o.application_id
+	`, appID, string(event)))
+	if err != nil {
+		return Offer{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Offer{}, err
+	}
+	return saved, nil
+}
+
+// ListOffers returns the user's offers on live applications, most recently updated first.
+// With ids, only those offers are returned (ids that don't match are skipped).
+func (s *dbStore) ListOffers(ctx context.Context, userID string, ids []string) ([]Offer, error) {
+	valid := []string{}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	if len(ids) > 0 && len(valid) == 0 {
+		return []Offer{}, nil
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		select `+offerColumns+`
+		from offers o
+		join applications a on a.id = o.application_id
+		where a.user_id = $1::uuid and a.deleted_at is null
+		  and (cardinality($2::uuid[]) = 0 or o.id = any($2::uuid[]))
+		order by o.updated_at desc, o.id
+	`, userID, valid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Offer{}
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
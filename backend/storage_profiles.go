@@ -3,21 +3,42 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
 )
 
-func (s *dbStore) GetProfile(ctx context.Context, userID string) (json.RawMessage, error) {
+func (s *dbStore) GetProfile(ctx context.Context, userID string) (json.RawMessage, int64, error) {
 	var raw []byte
-	if err := s.pool.QueryRow(ctx, `select profile from profiles where user_id = $1::uuid`, userID).Scan(&raw); err != nil {
-		return nil, err
+	var version int64
+	if err := s.pool.QueryRow(ctx, `select profile, version from profiles where user_id = $1::uuid`, userID).Scan(&raw, &version); err != nil {
+		return nil, 0, err
 	}
-	return json.RawMessage(raw), nil
+	return json.RawMessage(raw), version, nil
 }
 
-func (s *dbStore) UpsertProfile(ctx context.Context, userID string, profile json.RawMessage) error {
-	_, err := s.pool.Exec(ctx, `
-		insert into profiles (user_id, profile)
-		values ($1::uuid, $2::jsonb)
-		on conflict (user_id) do update set profile = excluded.profile, updated_at = now()
-	`, userID, string(profile))
-	return err
+// UpsertProfile saves the profile if its stored version equals ifMatch, where 0 means the
+// profile must not exist yet. It returns the new version, or errVersionConflict.
+func (s *dbStore) UpsertProfile(ctx context.Context, userID string, profile json.RawMessage, ifMatch int64) (int64, error) {
+	var version int64
+	var err error
+	if ifMatch == 0 {
+		err = s.pool.QueryRow(ctx, `
+			insert into profiles (user_id, profile, version)
+			values ($1::uuid, $2::jsonb, 1)
+			on conflict (user_id) do nothing
+			returning version
+		`, userID, string(profile)).Scan(&version)
+	} else {
+		err = s.pool.QueryRow(ctx, `
+			update profiles
+			set profile = $2::jsonb, version = version + 1, updated_at = now()
+			where user_id = $1::uuid and version = $3
+			returning version
+		`, userID, string(profile), ifMatch).Scan(&version)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errVersionConflict
+	}
+	return version, err
 }
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ListOpenSchedules returns the schedule of every live application that has not reached a
+// terminal status, for computing reminders.
+func (s *dbStore) ListOpenSchedules(ctx context.Context, userID string) ([]ApplicationSchedule, error) {
+	rows, err := s.pool.Query(ctx, `
+		select id::text, job_title, company, application_status,
+		       coalesce(to_char(deadline, 'YYYY-MM-DD'), ''), coalesce(to_char(applied_on, 'YYYY-MM-DD'), ''), interviews,
+		       `+statusChangedAtSQL+`
+		from applications a
+		where user_id = $1::uuid
+		  and deleted_at is null
+		  and application_status not in ('accepted', 'rejected', 'withdrawn')
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []ApplicationSchedule{}
+	for rows.Next() {
+		var sch ApplicationSchedule
+		var interviewsRaw []byte
+		if err := rows.Scan(&sch.ID, &sch.JobTitle, &sch.Company, &sch.ApplicationStatus,
+			&sch.Deadline, &sch.AppliedOn, &interviewsRaw, &sch.StatusChangedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(interviewsRaw, &sch.Interviews); err != nil {
+			return nil, err
+		}
+		out = append(out, sch)
+	}
+	return out, rows.Err()
+}
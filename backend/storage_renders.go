@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const renderColumns = `id::text, application_id::text, application_version,
+	resume_filename, resume_sha256, resume_size, resume_blob_key,
+	cover_filename, cover_sha256, cover_size, cover_blob_key, created_at`
+
+func scanRender(row pgx.Row, extra ...any) (PDFRender, error) {
+	var p PDFRender
+	dest := append([]any{&p.ID, &p.ApplicationID, &p.ApplicationVersion,
+		&p.ResumeFilename, &p.ResumeSHA256, &p.ResumeSize, &p.ResumeBlobKey,
+		&p.CoverFilename, &p.CoverSHA256, &p.CoverSize, &p.CoverBlobKey, &p.CreatedAt}, extra...)
+	err := row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PDFRender{}, errNotFound
+	}
+	return p, err
+}
+
+// CreateRender records a render whose PDFs are already in the blob store.
+func (s *dbStore) CreateRender(ctx context.Context, userID string, p PDFRender) (PDFRender, error) {
+	if _, err := uuid.Parse(p.ApplicationID); err != nil {
+		return PDFRender{}, errNotFound
+	}
+	var input []byte
+	saved, err := scanRender(s.pool.QueryRow(ctx, `
+		insert into pdf_renders (id, application_id, user_id, application_version, input,
+		                         resume_filename, resume_sha256, resume_size, resume_blob_key,
+		                         cover_filename, cover_sha256, cover_size, cover_blob_key)
+		select $1::uuid, a.id, a.user_id, $4, $5::jsonb, $6, $7, $8, $9, $10, $11, $12, $13
+		from applications a
+		where a.user_id = $2::uuid and a.id = $3::uuid and a.deleted_at is null
+		returning `+renderColumns+`, input`,
+		p.ID, userID, p.ApplicationID, p.ApplicationVersion, string(p.Input),
+		p.ResumeFilename, p.ResumeSHA256, p.ResumeSize, p.ResumeBlobKey,
+		p.CoverFilename, p.CoverSHA256, p.CoverSize, p.CoverBlobKey), &input)
+	if err != nil {
+		return PDFRender{}, err
+	}
+	saved.Input = input
+	return saved, nil
+}
+
+// ListRenders returns an application's renders, newest first, without their input snapshots.
+func (s *dbStore) ListRenders(ctx context.Context, userID, appID string) ([]PDFRender, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return nil, errNotFound
+	}
+	var exists bool
+	err := s.pool.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, appID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		select `+renderColumns+`
+		from pdf_renders
+		where user_id = $1::uuid and application_id = $2::uuid
+		order by created_at desc, id
+	`, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []PDFRender{}
+	for rows.Next() {
+		p, err := scanRender(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (s *dbStore) GetRender(ctx context.Context, userID, appID, id string) (PDFRender, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return PDFRender{}, errNotFound
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return PDFRender{}, errNotFound
+	}
+	var input []byte
+	p, err := scanRender(s.pool.QueryRow(ctx, `
+		select `+renderColumns+`, input
+		from pdf_renders r
+		where r.user_id = $1::uuid and r.application_id = $2::uuid and r.id = $3::uuid
+		  and exists (select 1 from applications a where a.id = r.application_id and a.deleted_at is null)
+	`, userID, appID, id), &input)
+	if err != nil {
+		return PDFRender{}, err
+	}
+	p.Input = input
+	return p, nil
+}
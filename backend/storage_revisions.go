@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func insertRevision(ctx context.Context, tx pgx.Tx, userID, appID, source string, restoredFrom *int64, resume []byte, cover []byte) error {
+	_, err := tx.Exec(ctx, `
+		insert into application_revisions (application_id, user_id, source, restored_from, resume, cover_letter, created_at)
+		values ($1::uuid, $2::uuid, $3, $4, $5::jsonb, $6::jsonb, now())
+	`, appID, userID, source, restoredFrom, string(resume), nullableJSONB(cover))
+	return err
+}
+
+func (s *dbStore) ListRevisions(ctx context.Context, userID, appID string) ([]RevisionSummary, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return nil, errNotFound
+	}
+	var exists bool
+	err := s.pool.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, appID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		select id, source, restored_from, created_at
+		from application_revisions
+		where user_id = $1::uuid and application_id = $2::uuid
+		order by id desc
+	`, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []RevisionSummary{}
+	for rows.Next() {
+		var rev RevisionSummary
+		if err := rows.Scan(&rev.ID, &rev.Source, &rev.RestoredFrom, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *dbStore) GetRevision(ctx context.Context, userID, appID string, revID int64) (Revision, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Revision{}, errNotFound
+	}
+	var rev Revision
+	var resumeRaw []byte
+	var coverRaw []byte
+	err := s.pool.QueryRow(ctx, `
+		select r.id, r.source, r.restored_from, r.created_at, r.resume, r.cover_letter
+		from application_revisions r
+		join applications a on a.id = r.application_id
+		where r.user_id = $1::uuid and r.application_id = $2::uuid and r.id = $3 and a.deleted_at is null
+	`, userID, appID, revID).Scan(&rev.ID, &rev.Source, &rev.RestoredFrom, &rev.CreatedAt, &resumeRaw, &coverRaw)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Revision{}, errNotFound
+		}
+		return Revision{}, err
+	}
+
+	if err := json.Unmarshal(resumeRaw, &rev.Resume); err != nil {
+		return Revision{}, err
+	}
+	if len(coverRaw) != 0 {
+		var cl CoverLetter
+		if err := json.Unmarshal(coverRaw, &cl); err != nil {
+			return Revision{}, err
+		}
+		rev.CoverLetter = &cl
+	}
+	return rev, nil
+}
+
+// RestoreRevision copies a revision's resume and cover letter back onto the application
+// and appends a new "restore" revision pointing at it.
+func (s *dbStore) RestoreRevision(ctx context.Context, userID, appID string, revID, ifMatch int64) (Application, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return Application{}, errNotFound
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Application{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var resumeRaw []byte
+	var coverRaw []byte
+	var version int64
+	err = tx.QueryRow(ctx, `
+		select r.resume, r.cover_letter, a.version
+		from application_revisions r
+		join applications a on a.id = r.application_id
+		where r.user_id = $1::uuid and r.application_id = $2::uuid and r.id = $3 and a.deleted_at is null
+		for update of a
+	`, userID, appID, revID).Scan(&resumeRaw, &coverRaw, &version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Application{}, errNotFound
+		}
+		return Application{}, err
+	}
+	if version != ifMatch {
+		return Application{}, errVersionConflict
+	}
+
+	_, err = tx.Exec(ctx, `
+		update applications
+		set resume = $3::jsonb,
+		    cover_letter = $4::jsonb,
+		    version = version + 1,
+		    updated_at = now()
+		where user_id = $1::uuid and id = $2::uuid
+	`, userID, appID, string(resumeRaw), nullableJSONB(coverRaw))
+	if err != nil {
+		return Application{}, err
+	}
+	if err := insertRevision(ctx, tx, userID, appID, revisionSourceRestore, &revID, resumeRaw, coverRaw); err != nil {
+		return Application{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Application{}, err
+	}
+
+	return s.GetApplication(ctx, userID, appID)
+}
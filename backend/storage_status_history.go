@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func insertStatusChange(ctx context.Context, tx pgx.Tx, userID, appID string, from *string, to string) error {
+	_, err := tx.Exec(ctx, `
+		insert into application_status_history (application_id, user_id, from_status, to_status, changed_at)
+		values ($1::uuid, $2::uuid, $3, $4, now())
+	`, appID, userID, from, to)
+	return err
+}
+
+func (s *dbStore) ListStatusHistory(ctx context.Context, userID, appID string) ([]StatusChange, error) {
+	if _, err := uuid.Parse(appID); err != nil {
+		return nil, errNotFound
+	}
+	var exists bool
+	err := s.pool.QueryRow(ctx, `
+		select true from applications where user_id = $1::uuid and id = $2::uuid and deleted_at is null
+	`, userID, appID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		select from_status, to_status, changed_at
+		from application_status_history
+		where user_id = $1::uuid and application_id = $2::uuid
+		order by changed_at asc, id asc
+	`, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []StatusChange{}
+	for rows.Next() {
+		var c StatusChange
+		if err := rows.Scan(&c.FromStatus, &c.ToStatus, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// rowQuerier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type rowQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// setApplicationTags replaces an application's tags, creating tags the user doesn't have yet.
+// A non-blank color updates the tag for every application. It returns the stored tags.
+func setApplicationTags(ctx context.Context, tx pgx.Tx, userID, appID string, tags []Tag) ([]Tag, error) {
+	if _, err := tx.Exec(ctx, `delete from application_tags where application_id = $1::uuid`, appID); err != nil {
+		return nil, err
+	}
+
+	out := make([]Tag, 0, len(tags))
+	for _, t := range tags {
+		var id int64
+		var saved Tag
+		err := tx.QueryRow(ctx, `
+			insert into tags (user_id, name, color)
+			values ($1::uuid, $2, coalesce(nullif($3, ''), $4))
+			on conflict (user_id, lower(name)) do update
+			set color = coalesce(nullif($3, ''), tags.color)
+			returning id, name, color
+		`, userID, t.Name, t.Color, defaultTagColor).Scan(&id, &saved.Name, &saved.Color)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(ctx, `
+			insert into application_tags (application_id, tag_id, user_id)
+			values ($1::uuid, $2, $3::uuid)
+		`, appID, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, saved)
+	}
+	sortTags(out)
+	return out, nil
+}
+
+// loadApplicationTags returns the tags of each of the given applications, sorted by name.
+func loadApplicationTags(ctx context.Context, q rowQuerier, userID string, appIDs []string) (map[string][]Tag, error) {
+	out := make(map[string][]Tag, len(appIDs))
+	for _, id := range appIDs {
+		out[id] = []Tag{}
+	}
+	if len(appIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := q.Query(ctx, `
+		select at.application_id::text, t.name, t.color
+		from application_tags at
+		join tags t on t.id = at.tag_id
+		where at.user_id = $1::uuid and at.application_id = any($2::uuid[])
+		order by lower(t.name)
+	`, userID, appIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var appID string
+		var t Tag
+		if err := rows.Scan(&appID, &t.Name, &t.Color); err != nil {
+			return nil, err
+		}
+		out[appID] = append(out[appID], t)
+	}
+	return out, rows.Err()
+}
+
+// ListTagCounts returns the user's tags that are on at least one live application, most used first.
+func (s *dbStore) ListTagCounts(ctx context.Context, userID string) ([]TagCount, error) {
+	rows, err := s.pool.Query(ctx, `
+		select t.name, t.color, count(*)
+		from tags t
+		join application_tags at on at.tag_id = t.id
+		join applications a on a.id = at.application_id and a.deleted_at is null
+		where t.user_id = $1::uuid
+		group by t.id, t.name, t.color
+		order by count(*) desc, lower(t.name)
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []TagCount{}
+	for rows.Next() {
+		var tc TagCount
+		if err := rows.Scan(&tc.Name, &tc.Color, &tc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, tc)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store is the persistence layer used by the HTTP handlers.
+// dbStore (Postgres/Supabase) is the production implementation; memoryStore keeps
+// everything in process for local development and tests.
+type Store interface {
+	ListApplicationSummaries(ctx context.Context, userID string, q ApplicationListQuery) (ApplicationPage, error)
+	CreateApplication(ctx context.Context, userID string, app Application) (Application, error)
+	GetApplication(ctx context.Context, userID, id string) (Application, error)
+	UpdateApplication(ctx context.Context, userID string, app Application, source string) (Application, error)
+	// DeleteApplication moves an application to the trash; RestoreApplication brings it back.
+	// Trashed applications are hidden from everything except trash listings.
+	DeleteApplication(ctx context.Context, userID, id string) error
+	RestoreApplication(ctx context.Context, userID, id string) (Application, error)
+	// PurgeTrash permanently removes applications of all users trashed before cutoff, returning
+	// the blob keys of their attachments and PDF renders.
+	PurgeTrash(ctx context.Context, cutoff time.Time) (purged int64, blobKeys []string, err error)
+	// ImportApplication stores app under its existing id. It reports created=false when the
+	// user already has an application with that id. If another user does, the application is
+	// stored under importFallbackID instead, so importing it again is still skipped.
+	ImportApplication(ctx context.Context, userID string, app Application) (saved Application, created bool, err error)
+
+	ListStatusHistory(ctx context.Context, userID, appID string) ([]StatusChange, error)
+	// ListOpenSchedules returns dates and interviews of live, non-terminal applications.
+	ListOpenSchedules(ctx context.Context, userID string) ([]ApplicationSchedule, error)
+
+	ListRevisions(ctx context.Context, userID, appID string) ([]RevisionSummary, error)
+	GetRevision(ctx context.Context, userID, appID string, revID int64) (Revision, error)
+	// RestoreRevision copies a revision's content onto the application, which must still be at
+	// version ifMatch; otherwise errVersionConflict is returned.
+	RestoreRevision(ctx context.Context, userID, appID string, revID, ifMatch int64) (Application, error)
+
+	// Analytics computes funnel, conversion, time-in-stage and volume statistics over the
+	// user's live applications matching q.
+	Analytics(ctx context.Context, userID string, q AnalyticsQuery) (Analytics, error)
+
+	// ListTagCounts returns tags on at least one live application, with how many use each.
+	ListTagCounts(ctx context.Context, userID string) ([]TagCount, error)
+
+	ListContacts(ctx context.Context, userID string) ([]Contact, error)
+	CreateContact(ctx context.Context, userID string, c Contact) (Contact, error)
+	GetContact(ctx context.Context, userID, id string) (Contact, error)
+	UpdateContact(ctx context.Context, userID string, c Contact) (Contact, error)
+	DeleteContact(ctx context.Context, userID, id string) error
+	// Contacts link to applications many-to-many; each application has at most one primary contact.
+	ListApplicationContacts(ctx context.Context, userID, appID string) ([]LinkedContact, error)
+	LinkContact(ctx context.Context, userID, appID, contactID string, primary *bool) (LinkedContact, error)
+	UnlinkContact(ctx context.Context, userID, appID, contactID string) error
+	PrimaryContact(ctx context.Context, userID, appID string) (Contact, error)
+
+	// Attachment records; the bytes live in a BlobStore under Attachment.BlobKey.
+	ListAttachments(ctx context.Context, userID, appID string) ([]Attachment, error)
+	CreateAttachment(ctx context.Context, userID string, a Attachment, quotaBytes int64) (Attachment, error)
+	GetAttachment(ctx context.Context, userID, appID, id string) (Attachment, error)
+	DeleteAttachment(ctx context.Context, userID, appID, id string) (Attachment, error)
+	AttachmentUsage(ctx context.Context, userID string) (usedBytes int64, err error)
+
+	// PDF renders are saved resume/cover letter PDFs with the input they were generated from.
+	CreateRender(ctx context.Context, userID string, p PDFRender) (PDFRender, error)
+	ListRenders(ctx context.Context, userID, appID string) ([]PDFRender, error)
+	GetRender(ctx context.Context, userID, appID, id string) (PDFRender, error)
+
+	// Each application has at most one offer. PutOffer reports whether it created one; the
+	// negotiation history only grows through AppendNegotiation.
+	GetOffer(ctx context.Context, userID, appID string) (Offer, error)
+	PutOffer(ctx context.Context, userID, appID string, o Offer) (saved Offer, created bool, err error)
+	DeleteOffer(ctx context.Context, userID, appID string) error
+	AppendNegotiation(ctx context.Context, userID, appID string, e NegotiationEvent) (Offer, error)
+	ListOffers(ctx context.Context, userID string, ids []string) ([]Offer, error)
+
+	// Calendar feed tokens are stored hashed; a user has at most one at a time.
+	SetCalendarToken(ctx context.Context, userID string, tokenHash []byte) (createdAt time.Time, err error)
+	RevokeCalendarToken(ctx context.Context, userID string) error
+	UserIDForCalendarToken(ctx context.Context, tokenHash []byte) (userID string, err error)
+
+	// Deleting an account needs an unexpired token from SetAccountDeletionToken. DeleteAccount
+	// removes everything the user owns in one transaction and returns the blob keys to delete.
+	SetAccountDeletionToken(ctx context.Context, userID string, tokenHash []byte, expiresAt time.Time) error
+	DeleteAccount(ctx context.Context, userID string, tokenHash []byte) (AccountDeletionReceipt, []string, error)
+
+	GetProfile(ctx context.Context, userID string) (profile json.RawMessage, version int64, err error)
+	UpsertProfile(ctx context.Context, userID string, profile json.RawMessage, ifMatch int64) (version int64, err error)
+
+	Close()
+}
+
+var (
+	_ Store = (*dbStore)(nil)
+	_ Store = (*memoryStore)(nil)
+)
+
+const (
+	storeBackendPostgres = "postgres"
+	storeBackendMemory   = "memory"
+)
+
+// storeBackendFromEnv reads STORE_BACKEND ("postgres" by default, or "memory").
+func storeBackendFromEnv() (string, error) {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv("STORE_BACKEND")))
+	switch backend {
+	case "", storeBackendPostgres:
+		return storeBackendPostgres, nil
+	case storeBackendMemory:
+		return storeBackendMemory, nil
+	default:
+		return "", fmt.Errorf("unknown STORE_BACKEND %q (use postgres or memory)", backend)
+	}
+}
+
+// currentStore returns the active store, or nil while the database is still connecting.
+func currentStore() Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return store
+}
+
+// importFallbackID is the id an imported application gets when its own id belongs to another
+// user. It is derived from the importing user and the original id, so it is the same every time.
+func importFallbackID(userID, id string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(userID+"\x00"+id)).String()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	maxTagNameLength = 50
+	defaultTagColor  = "#64748b"
+)
+
+var (
+	errInvalidTag = errors.New("invalid tag")
+	tagColorRE    = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+)
+
+// Tag is a user-defined label such as "remote" or "referral". Names are unique per user,
+// ignoring case; the color is a #rrggbb hex string shared by every application with the tag.
+type Tag struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// TagCount is a tag with the number of (non-trashed) applications using it.
+type TagCount struct {
+	Tag
+	Count int `json:"count"`
+}
+
+// normalizeTags trims and validates tags from a request, dropping case-insensitive duplicates.
+// A blank color means "keep the tag's existing color" (or use the default for a new tag).
+func normalizeTags(tags []Tag) ([]Tag, error) {
+	out := make([]Tag, 0, len(tags))
+	seen := map[string]int{}
+	for i, t := range tags {
+		t.Name = strings.Join(strings.Fields(t.Name), " ")
+		t.Color = strings.ToLower(strings.TrimSpace(t.Color))
+		if t.Name == "" {
+			return nil, fmt.Errorf("%w: tags[%d].name is required", errInvalidTag, i)
+		}
+		if len([]rune(t.Name)) > maxTagNameLength {
+			return nil, fmt.Errorf("%w: tags[%d].name is longer than %d characters", errInvalidTag, i, maxTagNameLength)
+		}
+		if t.Color != "" && !tagColorRE.MatchString(t.Color) {
+			return nil, fmt.Errorf("%w: tags[%d].color must be a #rrggbb hex color", errInvalidTag, i)
+		}
+		key := tagKey(t.Name)
+		if j, ok := seen[key]; ok {
+			if t.Color != "" {
+				out[j].Color = t.Color
+			}
+			continue
+		}
+		seen[key] = len(out)
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// tagKey is the case-insensitive identity of a tag name.
+func tagKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func sortTags(tags []Tag) {
+	sort.Slice(tags, func(i, j int) bool { return tagKey(tags[i].Name) < tagKey(tags[j].Name) })
+}
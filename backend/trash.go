@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	trashPurgeInterval        = time.Hour
+)
+
+// trashRetentionFromEnv reads TRASH_RETENTION_DAYS: how long deleted applications stay in the
+// trash before being purged. 0 disables purging.
+func trashRetentionFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv("TRASH_RETENTION_DAYS"))
+	if raw == "" {
+		return defaultTrashRetentionDays * 24 * time.Hour, nil
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid TRASH_RETENTION_DAYS %q (want a whole number of days, 0 to disable)", raw)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// purgeTrash permanently removes applications trashed longer than retention ago, along with
+// their attachment blobs. A blob that fails to delete is logged and left behind.
+func purgeTrash(ctx context.Context, s Store, b BlobStore, retention time.Duration) (int64, error) {
+	n, blobKeys, err := s.PurgeTrash(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range blobKeys {
+		if err := b.Delete(ctx, key); err != nil {
+			loggerFrom(ctx).Warn("trash purge: delete blob failed", "blob", key, "err", err)
+		}
+	}
+	return n, nil
+}
+
+// runTrashPurger purges expired trash once per trashPurgeInterval until ctx is cancelled.
+func runTrashPurger(ctx context.Context, b BlobStore, retention time.Duration) {
+	t := time.NewTicker(trashPurgeInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		s := currentStore()
+		if s == nil {
+			continue
+		}
+		purgeCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		n, err := purgeTrash(purgeCtx, s, b, retention)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("trash purge failed", "err", err)
+			}
+			continue
+		}
+		if n > 0 {
+			slog.Info("purged trashed applications", "count", n)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxJSONBodySize caps JSON request bodies. An application at the field limits below is well
+// under it, so only runaway payloads hit it.
+const maxJSONBodySize = 1 << 20
+
+// Field limits for applications, resumes, cover letters and the optimize requests. Lengths
+// are in characters.
+const (
+	maxShortTextLength      = 200   // names, titles, dates, locations, skills, courses
+	maxLineLength           = 1000  // a bullet point, a project's tech line, an address
+	maxParagraphLength      = 5000  // the objective, a cover letter paragraph, interview notes
+	maxJobDescriptionLength = 50000 // a pasted job posting
+	maxEntries              = 50    // jobs, projects, education entries, skill categories, paragraphs, interviews, tags
+	maxListItems            = 50    // bullet points, skills or courses in one list
+
+	// maxFieldErrors bounds the details of a single response; further errors are dropped.
+	maxFieldErrors = 100
+)
+
+// decodeJSONBody decodes the request body into dst. Bodies over maxJSONBodySize, unknown
+// fields and trailing data after the JSON value are rejected. The error is ready for writeError.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodySize)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return newAPIError(http.StatusRequestEntityTooLarge, codePayloadTooLarge,
+				fmt.Sprintf("request body is larger than %s", formatMB(tooLarge.Limit)))
+		}
+		return invalidJSON(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return badRequest("invalid JSON body: unexpected data after the JSON value")
+	}
+	return nil
+}
+
+// fieldError is one invalid field. Path uses the JSON names and indexes, e.g.
+// resume.jobs[2].jobPoints[0].
+type fieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// fieldErrors collects every problem with a request so the client can show them all at once.
+type fieldErrors []fieldError
+
+func (errs *fieldErrors) add(path, format string, args ...any) {
+	if len(*errs) < maxFieldErrors {
+		*errs = append(*errs, fieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// text checks a string's length and, if required, that it isn't blank.
+func (errs *fieldErrors) text(path, value string, max int, required bool) {
+	switch {
+	case required && strings.TrimSpace(value) == "":
+		errs.add(path, "is required")
+	case utf8.RuneCountInString(value) > max:
+		errs.add(path, "must be at most %d characters", max)
+	}
+}
+
+// count checks the number of entries in a list.
+func (errs *fieldErrors) count(path string, n, max int) {
+	if n > max {
+		errs.add(path, "must have at most %d entries", max)
+	}
+}
+
+// items checks a list of strings: its length and each item's.
+func (errs *fieldErrors) items(path string, items []string, max, maxItemLength int) {
+	errs.count(path, len(items), max)
+	for i, item := range items {
+		errs.text(fmt.Sprintf("%s[%d]", path, i), item, maxItemLength, false)
+	}
+}
+
+func (errs *fieldErrors) email(path, value string) {
+	if value == "" {
+		return
+	}
+	if addr, err := mail.ParseAddress(value); err != nil || addr.Name != "" || addr.Address != value {
+		errs.add(path, "must be an email address like name@example.com")
+		return
+	}
+	errs.text(path, value, maxShortTextLength, false)
+}
+
+// phoneRE allows digits with the usual separators, an optional leading + and an optional
+// extension ("x123" or "ext. 123").
+var phoneRE = regexp.MustCompile(`(?i)^(\+?[0-9 ().\-/]+?)\s*(?:(?:x|ext\.?)\s*[0-9]{1,6})?$`)
+
+func (errs *fieldErrors) phone(path, value string) {
+	if value == "" {
+		return
+	}
+	m := phoneRE.FindStringSubmatch(value)
+	digits := 0
+	if m != nil {
+		for _, c := range m[1] {
+			if c >= '0' && c <= '9' {
+				digits++
+			}
+		}
+	}
+	if digits < 7 || digits > 15 {
+		errs.add(path, "must be a phone number of 7 to 15 digits, e.g. +1 (555) 123-4567")
+	}
+}
+
+// link accepts http(s) URLs and scheme-less ones such as linkedin.com/in/jane, which is how the
+// frontend stores profile links.
+func (errs *fieldErrors) link(path, value string) {
+	if value == "" {
+		return
+	}
+	raw := value
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Hostname(), ".") || strings.ContainsAny(value, " \t\r\n") {
+		errs.add(path, "must be a URL like https://example.com/page")
+		return
+	}
+	errs.text(path, value, maxLineLength, false)
+}
+
+// err returns a validation_failed error listing every field, or nil.
+func (errs fieldErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	message := errs[0].Path + " " + errs[0].Message
+	if len(errs) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(errs)-1)
+	}
+	return newAPIError(http.StatusBadRequest, codeValidation, message).
+		withDetails(map[string]any{"fields": []fieldError(errs)})
+}
+
+// validateApplication checks an application posted for saving or rendering. Status, dates,
+// interview formats and tag names are checked when the application is saved.
+func validateApplication(app Application) error {
+	var errs fieldErrors
+	errs.text("jobTitle", app.JobTitle, maxShortTextLength, true)
+	errs.text("company", app.Company, maxShortTextLength, true)
+	errs.text("jobDescription", app.JobDescription, maxJobDescriptionLength, false)
+	errs.resume("resume", app.Resume)
+	if app.CoverLetter != nil {
+		errs.coverLetter("coverLetter", *app.CoverLetter)
+	}
+	errs.count("interviews", len(app.Interviews), maxEntries)
+	for i, round := range app.Interviews {
+		path := fmt.Sprintf("interviews[%d]", i)
+		errs.text(path+".interviewer", round.Interviewer, maxShortTextLength, false)
+		errs.text(path+".notes", round.Notes, maxParagraphLength, false)
+	}
+	errs.count("tags", len(app.Tags), maxEntries)
+	return errs.err()
+}
+
+// validateOptimizeRequest checks a POST /api/optimize-resume body.
+func validateOptimizeRequest(req optimizeRequest) error {
+	var errs fieldErrors
+	errs.job(req.JobTitle, req.Company, req.JobDescription)
+	errs.resume("resume", req.Resume)
+	return errs.err()
+}
+
+// validateOptimizeCoverLetterRequest checks a POST /api/optimize-coverletter body.
+func validateOptimizeCoverLetterRequest(req optimizeCoverLetterRequest) error {
+	var errs fieldErrors
+	errs.job(req.JobTitle, req.Company, req.JobDescription)
+	errs.resume("resume", req.Resume)
+	if req.CoverLetter != nil {
+		errs.coverLetter("coverLetter", *req.CoverLetter)
+	}
+	return errs.err()
+}
+
+// job checks the posting an optimize request tailors to; the AI needs its description.
+func (errs *fieldErrors) job(title, company, description string) {
+	errs.text("jobTitle", title, maxShortTextLength, false)
+	errs.text("company", company, maxShortTextLength, false)
+	errs.text("jobDescription", description, maxJobDescriptionLength, true)
+}
+
+func (errs *fieldErrors) resume(path string, res ResumeData) {
+	errs.text(path+".name", res.Name, maxShortTextLength, false)
+	errs.phone(path+".number", res.Phone)
+	errs.email(path+".email", res.Email)
+	errs.link(path+".linkedin", res.LinkedIn)
+	errs.link(path+".github", res.Github)
+	errs.text(path+".location", res.Location, maxShortTextLength, false)
+	errs.text(path+".objective", res.Objective, maxParagraphLength, false)
+	errs.items(path+".relevantCourses", res.RelevantCourses, maxListItems, maxShortTextLength)
+
+	errs.count(path+".education", len(res.Education), maxEntries)
+	for i, e := range res.Education {
+		p := fmt.Sprintf("%s.education[%d]", path, i)
+		errs.text(p+".institution", e.Institution, maxShortTextLength, true)
+		errs.text(p+".degree", e.Degree, maxShortTextLength, false)
+		errs.text(p+".field", e.Field, maxShortTextLength, false)
+		errs.text(p+".startDate", e.StartDate, maxShortTextLength, false)
+		errs.text(p+".endDate", e.EndDate, maxShortTextLength, false)
+		errs.text(p+".location", e.Location, maxShortTextLength, false)
+		errs.text(p+".gpa", e.GPA, maxShortTextLength, false)
+		errs.items(p+".courses", e.Courses, maxListItems, maxShortTextLength)
+	}
+
+	errs.count(path+".jobs", len(res.Jobs), maxEntries)
+	for i, job := range res.Jobs {
+		p := fmt.Sprintf("%s.jobs[%d]", path, i)
+		errs.text(p+".jobTitle", job.JobTitle, maxShortTextLength, true)
+		errs.text(p+".jobStartDate", job.JobStartDate, maxShortTextLength, false)
+		errs.text(p+".jobEndDate", job.JobEndDate, maxShortTextLength, false)
+		errs.text(p+".jobEmployer", job.JobEmployer, maxShortTextLength, false)
+		errs.text(p+".jobLocation", job.JobLocation, maxShortTextLength, false)
+		errs.items(p+".jobPoints", job.JobPoints, maxListItems, maxLineLength)
+	}
+
+	errs.count(path+".projects", len(res.Projects), maxEntries)
+	for i, project := range res.Projects {
+		p := fmt.Sprintf("%s.projects[%d]", path, i)
+		errs.text(p+".projectTitle", project.ProjectTitle, maxShortTextLength, true)
+		errs.text(p+".projectTech", project.ProjectTech, maxLineLength, false)
+		errs.text(p+".projectDate", project.ProjectDate, maxShortTextLength, false)
+		errs.items(p+".projectPoints", project.ProjectPoints, maxListItems, maxLineLength)
+	}
+
+	errs.count(path+".skillCategories", len(res.SkillCategories), maxEntries)
+	for i, cat := range res.SkillCategories {
+		p := fmt.Sprintf("%s.skillCategories[%d]", path, i)
+		errs.text(p+".catTitle", cat.CatTitle, maxShortTextLength, true)
+		errs.items(p+".catSkills", cat.CatSkills, maxListItems, maxShortTextLength)
+	}
+}
+
+func (errs *fieldErrors) coverLetter(path string, cl CoverLetter) {
+	errs.text(path+".hiringManagerName", cl.HiringManagerName, maxShortTextLength, false)
+	errs.text(path+".company", cl.Company, maxShortTextLength, false)
+	errs.text(path+".location", cl.Location, maxShortTextLength, false)
+	errs.text(path+".address", cl.Address, maxLineLength, false)
+	errs.text(path+".greeting", cl.Greeting, maxShortTextLength, false)
+	errs.items(path+".paragraphs", cl.Paragraphs, maxEntries, maxParagraphLength)
+	errs.text(path+".closing", cl.Closing, maxShortTextLength, false)
+}
@@ -10,6 +10,7 @@ const defaultResume = () => ({
     location: '',
     objective: '',
     relevantCourses: [],
+    education: [],
     jobs: [],
     projects: [],
     skillCategories: [],
@@ -159,9 +160,10 @@ const hydrateCandidateForEditor = (candidate) => {
     };
 };
 
+// The backend rejects unknown fields, so editor-only ids are dropped here.
 const normalizeResumeForBackend = (resume = defaultResume()) => {
     const safeResume = resume || {};
-    return {
+    return stripEditorIds({
         ...defaultResume(),
         ...safeResume,
         relevantCourses: normalizeList(safeResume.relevantCourses, ','),
@@ -180,7 +182,11 @@ const normalizeResumeForBackend = (resume = defaultResume()) => {
             ...cat,
             catSkills: normalizeList(cat.catSkills, ','),
         })),
-    };
+        education: (safeResume.education || []).map((entry) => ({
+            ...entry,
+            courses: normalizeList(entry.courses, ','),
+        })),
+    });
 };
 
 const stripEditorIds = (resume) => ({
@@ -188,6 +194,7 @@ const stripEditorIds = (resume) => ({
     jobs: (resume.jobs || []).map(({ id, ...rest }) => (void id, rest)),
     projects: (resume.projects || []).map(({ id, ...rest }) => (void id, rest)),
     skillCategories: (resume.skillCategories || []).map(({ id, ...rest }) => (void id, rest)),
+    education: (resume.education || []).map(({ id, ...rest }) => (void id, rest)),
 });
 
 const candidateToResume = (candidate) => ({
@@ -197,6 +204,16 @@ const candidateToResume = (candidate) => ({
     jobs: candidate?.jobs || [],
     projects: candidate?.projects || [],
     skillCategories: candidate?.skillCategories || [],
+    education: (candidate?.education || []).map((entry) => ({
+        institution: entry.school || '',
+        degree: entry.degree || '',
+        field: entry.field || '',
+        startDate: entry.startDate || '',
+        endDate: entry.endDate || '',
+        location: entry.location || '',
+        gpa: entry.gpa || '',
+        courses: normalizeList(entry.courses, ','),
+    })),
 });
 
 const normalizeApplicationForBackend = (application) => ({
@@ -255,6 +272,24 @@ const parseCoverLetterParagraphs = (text) => {
         .filter(Boolean);
 };
 
+// API errors are JSON envelopes: {code, message, details, requestId}. This returns the message
+// (or the raw body for anything else).
+const readApiError = async (response) => {
+    const text = await response.text().catch(() => '');
+    try {
+        const body = JSON.parse(text);
+        // validation_failed lists every invalid field, e.g. "resume.jobs[2].jobTitle is required".
+        const fields = body?.details?.fields;
+        if (Array.isArray(fields) && fields.length > 0) {
+            return fields.map((f) => `${f.path} ${f.message}`).join('\n');
+        }
+        if (body && typeof body.message === 'string') return body.message;
+    } catch {
+        // not JSON
+    }
+    return text;
+};
+
 // Profile card shown at the top of the sidebar
 const ProfileCard = ({ profile, onEdit }) => {
     const initials = profile.name
@@ -313,6 +348,8 @@ function App() {
             return '';
         }
     });
+    // Server version of the profile (ETag); null until the profile exists on the server.
+    const profileEtagRef = useRef(null);
     const [profile, setProfile] = useState(() => {
         try {
             const stored = localStorage.getItem('jobapp_profile');
@@ -397,11 +434,15 @@ function App() {
         if (!session?.access_token) return;
         try {
             const resp = await authedFetch('/api/profile');
-            if (resp.status === 404) return;
+            if (resp.status === 404) {
+                profileEtagRef.current = null;
+                return;
+            }
             if (!resp.ok) {
-                const text = await resp.text().catch(() => '');
+                const text = await readApiError(resp);
                 throw new Error(text || `HTTP error! status: ${resp.status}`);
             }
+            profileEtagRef.current = resp.headers.get('ETag');
             const data = await resp.json();
             const migratedCandidate = data?.candidate
                 ? hydrateCandidateForEditor(data.candidate)
@@ -420,13 +461,20 @@ function App() {
             return [];
         }
         try {
-            const response = await authedFetch('/api/applications');
-            if (!response.ok) {
-                const text = await response.text().catch(() => '');
-                throw new Error(text || `HTTP error! status: ${response.status}`);
-            }
-            const data = await response.json();
-            const list = Array.isArray(data) ? data : [];
+            // The backend pages results; follow X-Next-Cursor until the list is complete.
+            const list = [];
+            let cursor = '';
+            do {
+                const query = cursor ? `?limit=200&cursor=${encodeURIComponent(cursor)}` : '?limit=200';
+                const response = await authedFetch(`/api/applications${query}`);
+                if (!response.ok) {
+                    const text = await readApiError(response);
+                    throw new Error(text || `HTTP error! status: ${response.status}`);
+                }
+                const data = await response.json();
+                if (Array.isArray(data)) list.push(...data);
+                cursor = response.headers.get('X-Next-Cursor') || '';
+            } while (cursor);
             setApplications(list);
             return list;
         } catch (e) {
@@ -459,14 +507,15 @@ function App() {
                 body: JSON.stringify(payload),
             });
             if (!response.ok) {
-                throw new Error(`HTTP error! status: ${response.status}`);
+                const text = await readApiError(response);
+                throw new Error(text || `HTTP error! status: ${response.status}`);
             }
             const createdApp = await response.json();
             fetchApplications(); // Refresh the list
             navigate(`/application/${createdApp.id}`);
         } catch (e) {
             console.error("Failed to create application:", e);
-            alert("Failed to create new application.");
+            alert("Failed to create new application: " + e.message);
         }
     };
 
@@ -481,13 +530,23 @@ function App() {
             if (session?.access_token) {
                 const resp = await authedFetch('/api/profile', {
                     method: 'PUT',
-                    headers: { 'Content-Type': 'application/json' },
+                    headers: {
+                        'Content-Type': 'application/json',
+                        ...(profileEtagRef.current
+                            ? { 'If-Match': profileEtagRef.current }
+                            : { 'If-None-Match': '*' }),
+                    },
                     body: JSON.stringify(nextProfile),
                 });
+                if (resp.status === 412) {
+                    await loadProfileFromServer();
+                    throw new Error('Your profile was changed elsewhere. The latest version has been loaded; please re-apply your edits.');
+                }
                 if (!resp.ok) {
-                    const text = await resp.text().catch(() => '');
+                    const text = await readApiError(resp);
                     throw new Error(text || `HTTP error! status: ${resp.status}`);
                 }
+                profileEtagRef.current = resp.headers.get('ETag');
                 const saved = await resp.json();
                 setProfile({
                     ...defaultProfile(),
@@ -519,7 +578,7 @@ function App() {
     };
 
     const handleDeleteApplication = async (appId) => {
-        const confirmed = window.confirm('Are you sure you want to delete this application? This cannot be undone.');
+        const confirmed = window.confirm('Move this application to the trash? It can be restored until the trash is purged.');
         if (!confirmed) return;
         try {
             const response = await authedFetch(`/api/applications/${appId}`, {
@@ -540,6 +599,26 @@ function App() {
         }
     };
 
+    const handleCloneApplication = async (appId) => {
+        try {
+            const response = await authedFetch(`/api/applications/${appId}/clone`, {
+                method: 'POST',
+            });
+            if (!response.ok) {
+                throw new Error(`HTTP error! status: ${response.status}`);
+            }
+            const clone = await response.json();
+            setApplications((prev) => [
+                { id: clone.id, jobTitle: clone.jobTitle, company: clone.company, applicationStatus: clone.applicationStatus, tags: clone.tags, parentId: clone.parentId },
+                ...prev,
+            ]);
+            navigate(`/application/${clone.id}`);
+        } catch (e) {
+            console.error("Failed to clone application:", e);
+            alert('Failed to duplicate application.');
+        }
+    };
+
     useEffect(() => {
         if (authLoading) return;
         setError(null);
@@ -679,6 +758,15 @@ function App() {
                                         <p style={{ margin: 0, fontSize: '0.8em', color: '#666' }}>Status: {app.applicationStatus}</p>
                                     </div>
                                 </Link>
+                                <button
+                                    onClick={() => handleCloneApplication(app.id)}
+                                    className="btn btn--stretch"
+                                    style={{ minWidth: '38px' }}
+                                    aria-label={`Duplicate ${app.jobTitle}`}
+                                    title="Duplicate application"
+                                >
+                                    ⧉
+                                </button>
                                 <button
                                     onClick={() => handleDeleteApplication(app.id)}
                                     className="btn btn--danger btn--stretch"
@@ -746,6 +834,8 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
     const [optimizing, setOptimizing] = useState(false);
     const [optError, setOptError] = useState(null);
     const [optimizingCover, setOptimizingCover] = useState(false);
+    // Which tool produced the unsaved resume/cover letter changes (sent as X-Revision-Source).
+    const revisionSourceRef = useRef('manual');
     const [optCoverError, setOptCoverError] = useState(null);
     const [resumeEditorInitKey, setResumeEditorInitKey] = useState(0);
     const [previewDoc, setPreviewDoc] = useState('resume'); // 'resume' | 'cover'
@@ -831,20 +921,31 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
                 method: 'PUT',
                 headers: {
                     'Content-Type': 'application/json',
+                    'X-Revision-Source': revisionSourceRef.current,
+                    'If-Match': `"${updatedApp.version}"`,
                 },
                 body: JSON.stringify(payload),
             });
+            if (response.status === 412) {
+                const { details } = await response.json();
+                setApplication(details.current);
+                setResumeEditorInitKey((prev) => prev + 1);
+                alert('This application was changed elsewhere (another tab or an AI update). The latest version has been loaded; please re-apply your edits.');
+                return;
+            }
             if (!response.ok) {
-                throw new Error(`HTTP error! status: ${response.status}`);
+                const text = await readApiError(response);
+                throw new Error(text || `HTTP error! status: ${response.status}`);
             }
             const savedApp = await response.json();
+            revisionSourceRef.current = 'manual';
             setApplication(savedApp); // Update local state with saved data
             onApplicationUpdate(); // Refresh sidebar list
             alert('Application saved successfully!');
         } catch (e) {
             setError(e);
             console.error("Failed to save application:", e);
-            alert('Failed to save application.');
+            alert('Failed to save application: ' + e.message);
         }
     };
 
@@ -876,7 +977,7 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
             });
 
             if (!response.ok) {
-                const text = await response.text().catch(() => '');
+                const text = await readApiError(response);
                 throw new Error(text || `HTTP error! status: ${response.status}`);
             }
 
@@ -917,7 +1018,7 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
                 body: JSON.stringify(appForPdf),
             });
             if (!response.ok) {
-                const text = await response.text().catch(() => '');
+                const text = await readApiError(response);
                 throw new Error(text || `HTTP error! status: ${response.status}`);
             }
             const blob = await response.blob();
@@ -971,7 +1072,7 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
                 jobTitle: application.jobTitle,
                 company: application.company,
                 jobDescription: application.jobDescription,
-                resume: mergeProfileHeader(profileResume, profileHeader),
+                resume: normalizeResumeForBackend(mergeProfileHeader(profileResume, profileHeader)),
             };
             const response = await authedFetch('/api/optimize-resume', {
                 method: 'POST',
@@ -979,10 +1080,11 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
                 body: JSON.stringify(payload),
             });
             if (!response.ok) {
-                const text = await response.text().catch(() => '');
+                const text = await readApiError(response);
                 throw new Error(text || `HTTP ${response.status}`);
             }
             const optimizedResume = await response.json();
+            revisionSourceRef.current = 'ai_resume';
             setApplication((prev) => ({ ...prev, resume: mergeProfileHeader(optimizedResume, profileHeader) }));
             // Force the ResumeEditor to reload its internal state from the optimized resume.
             setResumeEditorInitKey((prev) => prev + 1);
@@ -1004,7 +1106,7 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
                 jobTitle: application.jobTitle,
                 company: application.company,
                 jobDescription: application.jobDescription,
-                resume: mergeProfileHeader(profileResume, profileHeader),
+                resume: normalizeResumeForBackend(mergeProfileHeader(profileResume, profileHeader)),
                 coverLetter: application.coverLetter || null,
             };
             const response = await authedFetch('/api/optimize-coverletter', {
@@ -1013,12 +1115,13 @@ function ApplicationDetail({ activeTab, setActiveTab, onApplicationUpdate, profi
                 body: JSON.stringify(payload),
             });
             if (!response.ok) {
-                const text = await response.text().catch(() => '');
+                const text = await readApiError(response);
                 throw new Error(text || `HTTP ${response.status}`);
             }
             const optimizedText = await response.text();
             const paragraphs = parseCoverLetterParagraphs(optimizedText);
             if (paragraphs.length === 0) throw new Error('No paragraphs returned from AI');
+            revisionSourceRef.current = 'ai_cover_letter';
             setApplication((prev) => ({
                 ...prev,
                 coverLetter: normalizeCoverLetter({ ...(prev.coverLetter || {}), paragraphs }),
@@ -2,10 +2,13 @@ import React, { useState, useEffect } from 'react';
 
 const ApplicationStatusOptions = [
     'applied',
-    'rejected',
+    'saved',
+    'screening',
     'interview',
     'offer',
     'accepted',
+    'rejected',
+    'withdrawn',
 ];
 
 function JobDetailsForm({ application, onSave }) {
@@ -13,6 +16,9 @@ function JobDetailsForm({ application, onSave }) {
     const [company, setCompany] = useState('');
     const [applicationStatus, setApplicationStatus] = useState(ApplicationStatusOptions[0]);
     const [jobDescription, setJobDescription] = useState('');
+    const [deadline, setDeadline] = useState('');
+    const [appliedOn, setAppliedOn] = useState('');
+    const [tags, setTags] = useState('');
 
     useEffect(() => {
         if (application) {
@@ -20,6 +26,9 @@ function JobDetailsForm({ application, onSave }) {
             setCompany(application.company);
             setApplicationStatus(application.applicationStatus);
             setJobDescription(application.jobDescription);
+            setDeadline(application.deadline || '');
+            setAppliedOn(application.appliedOn || '');
+            setTags((application.tags || []).map(tag => tag.name).join(', '));
         }
     }, [application]);
 
@@ -31,6 +40,9 @@ function JobDetailsForm({ application, onSave }) {
             company,
             applicationStatus,
             jobDescription,
+            deadline,
+            appliedOn,
+            tags: tags.split(',').map(name => name.trim()).filter(Boolean).map(name => ({ name })),
         });
     };
 
@@ -69,6 +81,44 @@ function JobDetailsForm({ application, onSave }) {
                     ))}
                 </select>
             </div>
+            <div style={{ display: 'flex', gap: '15px' }}>
+                <div>
+                    <label htmlFor="deadline" style={{ display: 'block', marginBottom: '5px' }}>Apply By:</label>
+                    <input
+                        type="date"
+                        id="deadline"
+                        value={deadline}
+                        onChange={(e) => setDeadline(e.target.value)}
+                        className="input"
+                    />
+                </div>
+                <div>
+                    <label htmlFor="appliedOn" style={{ display: 'block', marginBottom: '5px' }}>Applied On:</label>
+                    <input
+                        type="date"
+                        id="appliedOn"
+                        value={appliedOn}
+                        onChange={(e) => setAppliedOn(e.target.value)}
+                        className="input"
+                    />
+                </div>
+            </div>
+            <div>
+                <label htmlFor="tags" style={{ display: 'block', marginBottom: '5px' }}>Tags (comma-separated):</label>
+                <input
+                    type="text"
+                    id="tags"
+                    value={tags}
+                    onChange={(e) => setTags(e.target.value)}
+                    placeholder="remote, referral"
+                    className="input"
+                />
+            </div>
+            {application?.nextAction && application?.nextActionDue && (
+                <div>
+                    Next action: {application.nextAction.replace('_', ' ')} by {new Date(application.nextActionDue).toLocaleString()}
+                </div>
+            )}
             <div>
                 <label htmlFor="jobDescription" style={{ display: 'block', marginBottom: '5px' }}>Job Description:</label>
                 <textarea